@@ -0,0 +1,203 @@
+// Package accesslog writes HTTP access logs in the Apache common or combined
+// format, or as JSON lines, for analyzers that expect them. The middleware
+// packages (ginmw, echomw, fibermw, httpmw) provide an AccessLog middleware
+// writing to a Logger alongside the structured request logs.
+//
+//	access, err := accesslog.NewFile(logger.FileOptions{Filename: "logs/access.log"}, accesslog.FormatCombined)
+//	if err != nil {
+//		return err
+//	}
+//	defer access.Close()
+//	http.ListenAndServe(":8080", httpmw.AccessLog(access)(mux))
+package accesslog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/csmart-libs/go-logger"
+)
+
+// Format is the layout of access log lines
+type Format string
+
+const (
+	// FormatCommon is the Apache common log format
+	FormatCommon Format = "common"
+
+	// FormatCombined is the Apache combined log format: the common format
+	// followed by the referer and user agent
+	FormatCombined Format = "combined"
+
+	// FormatJSON writes one JSON object per request
+	FormatJSON Format = "json"
+)
+
+// Entry describes a handled request
+type Entry struct {
+	Time      time.Time
+	ClientIP  string
+	User      string
+	Method    string
+	URI       string
+	Proto     string
+	Status    int
+	Bytes     int64
+	Referer   string
+	UserAgent string
+	Latency   time.Duration
+	RequestID string
+}
+
+// Logger writes access log lines
+type Logger struct {
+	mu     sync.Mutex
+	w      io.Writer
+	format Format
+	close  func() error
+}
+
+// New creates a Logger writing to w
+func New(w io.Writer, format Format) (*Logger, error) {
+	switch format {
+	case FormatCommon, FormatCombined, FormatJSON:
+	default:
+		return nil, fmt.Errorf("accesslog: unknown format %q", format)
+	}
+	return &Logger{w: w, format: format}, nil
+}
+
+// NewFile creates a Logger writing to a dedicated file with the rotation
+// and retention of options
+func NewFile(options logger.FileOptions, format Format) (*Logger, error) {
+	file, err := logger.OpenFile(options)
+	if err != nil {
+		return nil, err
+	}
+	l, err := New(file, format)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	l.close = file.Close
+	return l, nil
+}
+
+// Log writes the line of an entry
+func (l *Logger) Log(e Entry) error {
+	var line []byte
+	if l.format == FormatJSON {
+		line = jsonLine(e)
+	} else {
+		line = apacheLine(e, l.format == FormatCombined)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err := l.w.Write(line)
+	return err
+}
+
+// Close closes the file of a Logger created by NewFile
+func (l *Logger) Close() error {
+	if l.close == nil {
+		return nil
+	}
+	return l.close()
+}
+
+// apacheLine formats an entry as
+//
+//	host - user [time] "request" status bytes ["referer" "user agent"]
+func apacheLine(e Entry, combined bool) []byte {
+	var b strings.Builder
+	b.WriteString(dash(e.ClientIP))
+	b.WriteString(" - ")
+	b.WriteString(dash(escape(e.User)))
+	b.WriteString(" [")
+	b.WriteString(e.Time.Format("02/Jan/2006:15:04:05 -0700"))
+	b.WriteString(`] "`)
+	b.WriteString(escape(e.Method + " " + e.URI + " " + e.Proto))
+	b.WriteString(`" `)
+	b.WriteString(strconv.Itoa(e.Status))
+	b.WriteByte(' ')
+	if e.Bytes > 0 {
+		b.WriteString(strconv.FormatInt(e.Bytes, 10))
+	} else {
+		b.WriteByte('-')
+	}
+	if combined {
+		b.WriteString(` "`)
+		b.WriteString(dash(escape(e.Referer)))
+		b.WriteString(`" "`)
+		b.WriteString(dash(escape(e.UserAgent)))
+		b.WriteByte('"')
+	}
+	b.WriteByte('\n')
+	return []byte(b.String())
+}
+
+// jsonEntry is the JSON access log schema
+type jsonEntry struct {
+	Time      string  `json:"time"`
+	ClientIP  string  `json:"client_ip"`
+	User      string  `json:"user,omitempty"`
+	Method    string  `json:"method"`
+	URI       string  `json:"uri"`
+	Proto     string  `json:"proto"`
+	Status    int     `json:"status"`
+	Bytes     int64   `json:"bytes"`
+	Referer   string  `json:"referer,omitempty"`
+	UserAgent string  `json:"user_agent,omitempty"`
+	LatencyMs float64 `json:"latency_ms"`
+	RequestID string  `json:"request_id,omitempty"`
+}
+
+func jsonLine(e Entry) []byte {
+	line, _ := json.Marshal(jsonEntry{
+		Time:      e.Time.Format(time.RFC3339Nano),
+		ClientIP:  e.ClientIP,
+		User:      e.User,
+		Method:    e.Method,
+		URI:       e.URI,
+		Proto:     e.Proto,
+		Status:    e.Status,
+		Bytes:     e.Bytes,
+		Referer:   e.Referer,
+		UserAgent: e.UserAgent,
+		LatencyMs: float64(e.Latency) / float64(time.Millisecond),
+		RequestID: e.RequestID,
+	})
+	return append(line, '\n')
+}
+
+// dash returns "-" for an empty value, as Apache does
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// escape escapes quotes, backslashes and control characters like Apache,
+// so that a value cannot break the line apart
+func escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c == 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Uploader stores rotated log files in object storage. The s3archive,
+// gcsarchive and azurearchive packages implement it for Amazon S3, Google
+// Cloud Storage and Azure Blob Storage.
+type Uploader interface {
+	// Upload stores the file at localPath under key. It returns an error
+	// unless the stored object is verified to match the file.
+	Upload(ctx context.Context, key, localPath string) error
+}
+
+// archive uploads a rotated file, retrying failed attempts, and removes the
+// local copy once uploaded if the options ask for it
+func archive(options ArchiveOptions, localPath string, at time.Time) error {
+	retries := options.Retries
+	if retries <= 0 {
+		retries = 3
+	}
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+	key := archiveKey(options.KeyPrefix, filepath.Base(localPath), at)
+
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err = options.Uploader.Upload(ctx, key, localPath)
+		cancel()
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("logger: archive %s: %w", localPath, err)
+	}
+
+	if options.DeleteLocal {
+		if err := os.Remove(localPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("logger: remove archived %s: %w", localPath, err)
+		}
+	}
+	return nil
+}
+
+// archiveKey expands the placeholders of the key prefix and joins it with
+// the file name
+func archiveKey(prefix, name string, at time.Time) string {
+	if prefix == "" {
+		return name
+	}
+	host, _ := os.Hostname()
+	prefix = strings.NewReplacer(
+		"{host}", host,
+		"{date}", at.Format("2006-01-02"),
+		"{year}", at.Format("2006"),
+		"{month}", at.Format("01"),
+		"{day}", at.Format("02"),
+		"{hour}", at.Format("15"),
+	).Replace(prefix)
+	return path.Join(prefix, name)
+}
@@ -0,0 +1,249 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultAsyncQueueSize is the number of entries the async queue holds
+// before logging calls block
+const DefaultAsyncQueueSize = 1024
+
+// DefaultAsyncReportInterval is how often a non-blocking queue reports the
+// entries it dropped
+const DefaultAsyncReportInterval = 10 * time.Second
+
+// asyncJob is an entry waiting to be encoded and written, or a flush marker
+type asyncJob struct {
+	checked *zapcore.CheckedEntry
+	entry   zapcore.Entry
+	fields  []zapcore.Field
+	flushed chan struct{}
+}
+
+// asyncQueue feeds entries to a worker goroutine that encodes and writes them
+type asyncQueue struct {
+	mu     sync.RWMutex
+	closed bool
+	jobs   chan asyncJob
+	done   chan struct{}
+
+	// nonBlocking queues drop entries that do not fit within maxWait
+	nonBlocking    bool
+	maxWait        time.Duration
+	reportInterval time.Duration
+	report         func(error)
+	metrics        MetricsRecorder
+	dropped        atomic.Int64
+}
+
+func newAsyncQueue(options AsyncOptions, report func(error), metrics MetricsRecorder) *asyncQueue {
+	size := options.QueueSize
+	if size <= 0 {
+		size = DefaultAsyncQueueSize
+	}
+	reportInterval := options.ReportInterval
+	if reportInterval <= 0 {
+		reportInterval = DefaultAsyncReportInterval
+	}
+	q := &asyncQueue{
+		jobs:           make(chan asyncJob, size),
+		done:           make(chan struct{}),
+		nonBlocking:    options.NonBlocking,
+		maxWait:        options.MaxWait,
+		reportInterval: reportInterval,
+		report:         report,
+		metrics:        metrics,
+	}
+	go q.run()
+	return q
+}
+
+func (q *asyncQueue) run() {
+	defer close(q.done)
+	defer q.reportDropped()
+
+	var ticks <-chan time.Time
+	if q.nonBlocking {
+		ticker := time.NewTicker(q.reportInterval)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+	for {
+		select {
+		case job, ok := <-q.jobs:
+			if !ok {
+				return
+			}
+			if job.flushed != nil {
+				close(job.flushed)
+				continue
+			}
+			if err := writeChecked(job.checked, job.entry, job.fields); err != nil {
+				q.report(err)
+			}
+		case <-ticks:
+			q.reportDropped()
+		}
+	}
+}
+
+// reportDropped reports the entries dropped since the last report
+func (q *asyncQueue) reportDropped() {
+	if n := q.dropped.Swap(0); n > 0 {
+		q.report(fmt.Errorf("logger: async queue full, dropped %d entries", n))
+	}
+}
+
+// enqueue hands an entry to the worker and writes in the caller once the
+// queue is closed. While the queue is full it blocks, or for non-blocking
+// queues waits up to maxWait and then drops the entry. Write errors of the
+// worker go to the internal error handler.
+func (q *asyncQueue) enqueue(job asyncJob) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return writeChecked(job.checked, job.entry, job.fields)
+	}
+	if !q.nonBlocking {
+		q.jobs <- job
+		return nil
+	}
+
+	select {
+	case q.jobs <- job:
+		return nil
+	default:
+	}
+	if q.maxWait > 0 {
+		timer := time.NewTimer(q.maxWait)
+		defer timer.Stop()
+		select {
+		case q.jobs <- job:
+			return nil
+		case <-timer.C:
+		}
+	}
+	q.dropped.Add(1)
+	q.metrics.RecordDropped(DropReasonQueueFull, 1)
+	return nil
+}
+
+// flush waits until the entries queued so far are written
+func (q *asyncQueue) flush() {
+	q.mu.RLock()
+	if q.closed {
+		q.mu.RUnlock()
+		return
+	}
+	flushed := make(chan struct{})
+	q.jobs <- asyncJob{flushed: flushed}
+	q.mu.RUnlock()
+	<-flushed
+}
+
+// close writes the queued entries and stops the worker
+func (q *asyncQueue) close() error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return nil
+	}
+	q.closed = true
+	close(q.jobs)
+	q.mu.Unlock()
+	<-q.done
+	return nil
+}
+
+// asyncCore moves encoding and writing of a core to the async worker, so
+// logging calls only pay for an enqueue. Entries at DPanic and above are
+// written synchronously after the queue is flushed, as the process may not
+// survive them.
+type asyncCore struct {
+	zapcore.Core
+	queue *asyncQueue
+}
+
+func newAsyncCore(core zapcore.Core, queue *asyncQueue) zapcore.Core {
+	return &asyncCore{Core: core, queue: queue}
+}
+
+func (c *asyncCore) With(fields []zapcore.Field) zapcore.Core {
+	return &asyncCore{Core: c.Core.With(fields), queue: c.queue}
+}
+
+func (c *asyncCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	inner := c.Core.Check(ent, nil)
+	if inner == nil {
+		return ce
+	}
+	return ce.AddCore(inner.Entry, &asyncWriter{queue: c.queue, checked: inner})
+}
+
+func (c *asyncCore) Sync() error {
+	c.queue.flush()
+	return c.Core.Sync()
+}
+
+// asyncWriter is a single-use core that queues one entry for the worker
+type asyncWriter struct {
+	queue   *asyncQueue
+	checked *zapcore.CheckedEntry
+}
+
+func (w *asyncWriter) Enabled(zapcore.Level) bool { return true }
+
+func (w *asyncWriter) With([]zapcore.Field) zapcore.Core { return w }
+
+func (w *asyncWriter) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, w)
+}
+
+func (w *asyncWriter) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if severityOf(ent.Level) >= zapcore.DPanicLevel {
+		w.queue.flush()
+		return writeChecked(w.checked, ent, fields)
+	}
+	return w.queue.enqueue(asyncJob{
+		checked: w.checked,
+		entry:   ent,
+		fields:  resolveFields(fields),
+	})
+}
+
+func (w *asyncWriter) Sync() error { return nil }
+
+// resolveFields copies fields for the worker, evaluating the ones computed
+// or read only when encoded (lazy fields, object and array marshalers and
+// Stringers) and copying byte slices, so that the entry holds the values of
+// the logging call, not those of the time the worker encodes it
+func resolveFields(fields []zapcore.Field) []zapcore.Field {
+	resolved := make([]zapcore.Field, 0, len(fields))
+	for _, f := range fields {
+		switch f.Type {
+		case zapcore.ObjectMarshalerType, zapcore.InlineMarshalerType, zapcore.ArrayMarshalerType, zapcore.StringerType:
+			enc := zapcore.NewMapObjectEncoder()
+			f.AddTo(enc)
+			for _, key := range slices.Sorted(maps.Keys(enc.Fields)) {
+				resolved = append(resolved, zap.Any(key, enc.Fields[key]))
+			}
+		case zapcore.ByteStringType, zapcore.BinaryType:
+			if b, ok := f.Interface.([]byte); ok {
+				f.Interface = bytes.Clone(b)
+			}
+			resolved = append(resolved, f)
+		default:
+			resolved = append(resolved, f)
+		}
+	}
+	return resolved
+}
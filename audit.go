@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Mandatory fields of audit events
+const (
+	AuditActor   = "actor"
+	AuditAction  = "action"
+	AuditTarget  = "target"
+	AuditOutcome = "outcome"
+)
+
+var auditRequiredFields = []string{AuditActor, AuditAction, AuditTarget, AuditOutcome}
+
+// auditLogger writes audit events to their dedicated sink
+type auditLogger struct {
+	core   zapcore.Core
+	report func(error)
+
+	// routeSecurity writes the security events to the audit log
+	routeSecurity bool
+}
+
+// newAuditLogger opens the audit file. Audit entries bypass sampling, rate
+// limiting, deduplication, level filters and hooks, so none is lost. The
+// file is never buffered and has no disk full policy or fallback output:
+// each event is synced to disk, and a failed write is an error.
+func newAuditLogger(config Config, encoders *encoderSet, fields []zap.Field) (*auditLogger, sink, error) {
+	options := config.Audit.File
+	if options.Name == "" {
+		options.Name = SinkAudit
+	}
+	options.BufferSize = 0
+	options.FlushInterval = 0
+	options.DiskFullPolicy = ""
+	auditSink, err := newFileSink(options.Name, options, config, true)
+	if err != nil {
+		return nil, sink{}, err
+	}
+	encoding := config.Audit.Encoding
+	if encoding == "" {
+		encoding = EncodingJSON
+	}
+	encoder, err := encoders.get(encoding)
+	if err != nil {
+		return nil, sink{}, err
+	}
+	core := zapcore.NewCore(encoder, auditSink.writer, zapcore.DebugLevel)
+	audit := &auditLogger{
+		core:   core.With(fields),
+		report: config.internalErrorHandler(),
+
+		routeSecurity: config.Security.RouteToAudit,
+	}
+	return audit, auditSink, nil
+}
+
+// write writes an event and syncs it to disk. A failure is reported to the
+// internal error handler and returned.
+func (a *auditLogger) write(event string, fields []zap.Field) error {
+	ent := zapcore.Entry{Level: zapcore.InfoLevel, Time: time.Now(), Message: event}
+	err := a.core.Write(ent, fields)
+	if err == nil {
+		err = a.core.Sync()
+	}
+	if err != nil {
+		err = fmt.Errorf("logger: write audit event %q: %w", event, err)
+		a.report(err)
+	}
+	return err
+}
+
+// Audit writes an audit event. Events must carry the actor, action, target
+// and outcome fields; missing ones are written as "unknown". With
+// Config.Audit.File set, events go to the audit file only, never sampled or
+// filtered, and missing fields are reported to the internal error handler;
+// otherwise they are written to the application log at info level. An
+// event that could not be written and synced to the audit file is reported
+// to the internal error handler and returned as an error, so that callers
+// can refuse an action that cannot be audited.
+func (l *ZapLogger) Audit(event string, fields ...zap.Field) error {
+	var missing []string
+	for _, key := range auditRequiredFields {
+		if !hasField(fields, key) {
+			missing = append(missing, key)
+			fields = append(fields[:len(fields):len(fields)], zap.String(key, "unknown"))
+		}
+	}
+	if l.audit == nil {
+		l.logger.Info(event, fields...)
+		return nil
+	}
+	if len(missing) > 0 {
+		l.audit.report(fmt.Errorf("logger: audit event %q is missing %s", event, strings.Join(missing, ", ")))
+	}
+	return l.audit.write(event, fields)
+}
+
+// hasField reports whether fields contain the key
+func hasField(fields []zap.Field, key string) bool {
+	for _, f := range fields {
+		if fieldKey(f) == key {
+			return true
+		}
+	}
+	return false
+}
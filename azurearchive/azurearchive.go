@@ -0,0 +1,100 @@
+// Package azurearchive uploads rotated log files to Azure Blob Storage.
+//
+//	cred, err := azidentity.NewDefaultAzureCredential(nil)
+//	if err != nil {
+//		return err
+//	}
+//	client, err := azblob.NewClient("https://myaccount.blob.core.windows.net/", cred, nil)
+//	if err != nil {
+//		return err
+//	}
+//	uploader := azurearchive.New(client, "logs")
+//	config := logger.ProductionConfigWithFile("logs/app.log").
+//		WithArchive(uploader, "app/{host}/{date}", true)
+package azurearchive
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+)
+
+// Uploader implements logger.Uploader for a blob container
+type Uploader struct {
+	client    *azblob.Client
+	container string
+}
+
+// New creates an Uploader storing files in container
+func New(client *azblob.Client, container string) *Uploader {
+	return &Uploader{client: client, container: container}
+}
+
+// Upload stores the file as the block blob named key with the MD5 checksum
+// of the file as its Content-MD5, then verifies the stored blob reports that
+// checksum and the size of the file. The service checks the content against
+// the MD5 checksum when the file fits in a single request, and each block
+// against its CRC64 checksum otherwise.
+func (u *Uploader) Upload(ctx context.Context, key, localPath string) error {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	checksum := hash.Sum(nil)
+
+	contentType := "text/plain"
+	if strings.HasSuffix(localPath, ".gz") {
+		contentType = "application/gzip"
+	}
+	headers := &blob.HTTPHeaders{BlobContentType: &contentType, BlobContentMD5: checksum}
+
+	blobClient := u.client.ServiceClient().NewContainerClient(u.container).NewBlockBlobClient(key)
+	if info.Size() <= blockblob.MaxUploadBlobBytes {
+		_, err = blobClient.Upload(ctx, f, &blockblob.UploadOptions{
+			HTTPHeaders:             headers,
+			TransactionalValidation: blob.TransferValidationTypeMD5(checksum),
+		})
+	} else {
+		_, err = blobClient.UploadFile(ctx, f, &blockblob.UploadFileOptions{
+			HTTPHeaders:             headers,
+			TransactionalValidation: blob.TransferValidationTypeComputeCRC64(),
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("azurearchive: upload %s/%s: %w", u.container, key, err)
+	}
+
+	props, err := blobClient.GetProperties(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("azurearchive: verify %s/%s: %w", u.container, key, err)
+	}
+	if props.ContentLength == nil || *props.ContentLength != info.Size() {
+		return fmt.Errorf("azurearchive: verify %s/%s: stored size does not match %d bytes", u.container, key, info.Size())
+	}
+	if !bytes.Equal(props.ContentMD5, checksum) {
+		return fmt.Errorf("azurearchive: verify %s/%s: stored MD5 checksum does not match", u.container, key)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+module github.com/csmart-libs/go-logger/azurearchive
+
+go 1.24.4
+
+require github.com/Azure/azure-sdk-for-go/sdk/storage/azblob v1.6.1
+
+require (
+	github.com/Azure/azure-sdk-for-go/sdk/azcore v1.18.0 // indirect
+	github.com/Azure/azure-sdk-for-go/sdk/internal v1.11.1 // indirect
+	golang.org/x/net v0.39.0 // indirect
+	golang.org/x/text v0.24.0 // indirect
+)
@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"errors"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultBacktraceSize is the number of entries kept by the backtrace buffer
+const DefaultBacktraceSize = 100
+
+// backtraceRecord is an entry held back by the backtrace buffer, with the
+// core carrying the context of the logger that produced it
+type backtraceRecord struct {
+	core   zapcore.Core
+	entry  zapcore.Entry
+	fields []zapcore.Field
+}
+
+// backtraceBuffer is a ring of the most recent entries below the level
+type backtraceBuffer struct {
+	mu      sync.Mutex
+	records []backtraceRecord
+	next    int
+	full    bool
+}
+
+func (b *backtraceBuffer) add(record backtraceRecord) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.records[b.next] = record
+	b.next = (b.next + 1) % len(b.records)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// drain returns the buffered records, oldest first, and empties the buffer
+func (b *backtraceBuffer) drain() []backtraceRecord {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var records []backtraceRecord
+	if b.full {
+		records = append(records, b.records[b.next:]...)
+	}
+	records = append(records, b.records[:b.next]...)
+	clear(b.records)
+	b.next, b.full = 0, false
+	return records
+}
+
+// backtraceCore enforces the named levels like namedLevelCore, but keeps
+// the entries it rejects in a ring buffer. When an entry at the trigger
+// level is logged, the buffered entries are written first with a
+// backtrace field, giving the context that led to the error.
+type backtraceCore struct {
+	zapcore.Core
+	levels  *namedLevels
+	trigger zapcore.Level
+	buffer  *backtraceBuffer
+}
+
+func newBacktraceCore(core zapcore.Core, levels *namedLevels, options BacktraceOptions) zapcore.Core {
+	size := options.Size
+	if size <= 0 {
+		size = DefaultBacktraceSize
+	}
+	trigger, err := ParseLevel(options.Level)
+	if options.Level == "" || err != nil {
+		trigger = zapcore.ErrorLevel
+	}
+	return &backtraceCore{
+		Core:    core,
+		levels:  levels,
+		trigger: trigger,
+		buffer:  &backtraceBuffer{records: make([]backtraceRecord, size)},
+	}
+}
+
+func (c *backtraceCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	return &clone
+}
+
+func (c *backtraceCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	rank := rankOf(ent.Level)
+	if rank < rankOf(c.levels.Level(ent.LoggerName)) {
+		if rank < rankOf(c.trigger) {
+			return ce.AddCore(ent, &backtraceRecorder{core: c})
+		}
+		return ce
+	}
+	if rank < rankOf(c.trigger) {
+		return c.Core.Check(ent, ce)
+	}
+	inner := c.Core.Check(ent, nil)
+	if inner == nil {
+		return ce
+	}
+	return ce.AddCore(inner.Entry, &backtraceDumper{core: c, checked: inner})
+}
+
+// dump writes the buffered entries through the cores that recorded them
+func (c *backtraceCore) dump() error {
+	var errs []error
+	for _, record := range c.buffer.drain() {
+		if checked := record.core.Check(record.entry, nil); checked != nil {
+			errs = append(errs, writeChecked(checked, record.entry, append(record.fields,
+				zapcore.Field{Key: "backtrace", Type: zapcore.BoolType, Integer: 1})))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// backtraceRecorder is a single-use core that buffers an entry
+type backtraceRecorder struct {
+	core *backtraceCore
+}
+
+func (w *backtraceRecorder) Enabled(zapcore.Level) bool { return true }
+
+func (w *backtraceRecorder) With([]zapcore.Field) zapcore.Core { return w }
+
+func (w *backtraceRecorder) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, w)
+}
+
+func (w *backtraceRecorder) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	w.core.buffer.add(backtraceRecord{
+		core:   w.core.Core,
+		entry:  ent,
+		fields: append([]zapcore.Field(nil), fields...),
+	})
+	return nil
+}
+
+func (w *backtraceRecorder) Sync() error { return nil }
+
+// backtraceDumper is a single-use core that writes the buffered entries
+// before the entry that triggered them
+type backtraceDumper struct {
+	core    *backtraceCore
+	checked *zapcore.CheckedEntry
+}
+
+func (w *backtraceDumper) Enabled(zapcore.Level) bool { return true }
+
+func (w *backtraceDumper) With([]zapcore.Field) zapcore.Core { return w }
+
+func (w *backtraceDumper) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, w)
+}
+
+func (w *backtraceDumper) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	err := w.core.dump()
+	return errors.Join(err, writeChecked(w.checked, ent, fields))
+}
+
+func (w *backtraceDumper) Sync() error { return nil }
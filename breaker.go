@@ -0,0 +1,144 @@
+package logger
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultBreakerCooldown is how long the circuit breaker stays open
+const DefaultBreakerCooldown = 30 * time.Second
+
+// volumeBreaker measures log volume per second and opens when it exceeds the
+// configured thresholds. While open, entries below its level are dropped.
+type volumeBreaker struct {
+	maxEntries int64
+	maxBytes   int64
+	level      zapcore.Level
+	cooldown   time.Duration
+
+	window    atomic.Int64 // unix second of the current window
+	entries   atomic.Int64
+	bytes     atomic.Int64
+	openUntil atomic.Int64 // unix nanoseconds; zero while closed
+	dropped   atomic.Int64
+	metrics   MetricsRecorder
+
+	// report receives the errors writing the breaker notices
+	report func(error)
+}
+
+func newVolumeBreaker(options CircuitBreakerOptions, metrics MetricsRecorder, report func(error)) *volumeBreaker {
+	level, err := ParseLevel(options.Level)
+	if options.Level == "" || err != nil {
+		level = zapcore.WarnLevel
+	}
+	cooldown := options.Cooldown
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+	return &volumeBreaker{
+		maxEntries: int64(options.MaxEntriesPerSecond),
+		maxBytes:   options.MaxBytesPerSecond,
+		level:      level,
+		cooldown:   cooldown,
+		metrics:    metrics,
+		report:     report,
+	}
+}
+
+// roll starts a new measurement window when the second changes
+func (b *volumeBreaker) roll(now time.Time) {
+	sec := now.Unix()
+	if w := b.window.Load(); w != sec && b.window.CompareAndSwap(w, sec) {
+		b.entries.Store(0)
+		b.bytes.Store(0)
+	}
+}
+
+// exceeded reports whether the current window is over a threshold
+func (b *volumeBreaker) exceeded() bool {
+	return (b.maxEntries > 0 && b.entries.Load() > b.maxEntries) ||
+		(b.maxBytes > 0 && b.bytes.Load() > b.maxBytes)
+}
+
+// writer counts the bytes written to w
+func (b *volumeBreaker) writer(w zapcore.WriteSyncer) zapcore.WriteSyncer {
+	return &countingWriter{WriteSyncer: w, breaker: b}
+}
+
+// countingWriter feeds the bytes written to a sink into the breaker
+type countingWriter struct {
+	zapcore.WriteSyncer
+	breaker *volumeBreaker
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.breaker.roll(time.Now())
+	w.breaker.bytes.Add(int64(len(p)))
+	return w.WriteSyncer.Write(p)
+}
+
+// breakerCore enforces a volumeBreaker in front of a core. It logs a single
+// notice when the breaker opens and another, with the number of dropped
+// entries, when it closes again.
+type breakerCore struct {
+	zapcore.Core
+	breaker *volumeBreaker
+}
+
+func newBreakerCore(core zapcore.Core, breaker *volumeBreaker) zapcore.Core {
+	return &breakerCore{Core: core, breaker: breaker}
+}
+
+func (c *breakerCore) With(fields []zapcore.Field) zapcore.Core {
+	return &breakerCore{Core: c.Core.With(fields), breaker: c.breaker}
+}
+
+func (c *breakerCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	b := c.breaker
+	if until := b.openUntil.Load(); until != 0 {
+		if ent.Time.UnixNano() < until {
+			if rankOf(ent.Level) < rankOf(b.level) {
+				b.dropped.Add(1)
+				b.metrics.RecordDropped(DropReasonBreaker, 1)
+				return ce
+			}
+		} else if b.openUntil.CompareAndSwap(until, 0) {
+			b.entries.Store(0)
+			b.bytes.Store(0)
+			c.notice(ent, "log breaker closed",
+				zapcore.Field{Key: "dropped", Type: zapcore.Int64Type, Integer: b.dropped.Swap(0)})
+		}
+	}
+
+	checked := c.Core.Check(ent, ce)
+	if checked == nil {
+		return nil
+	}
+	b.roll(ent.Time)
+	b.entries.Add(1)
+	if b.exceeded() && b.openUntil.CompareAndSwap(0, ent.Time.Add(b.cooldown).UnixNano()) {
+		c.notice(ent, "log breaker open",
+			zapcore.Field{Key: "breaker_level", Type: zapcore.StringType, String: levelName(b.level)},
+			zapcore.Field{Key: "cooldown", Type: zapcore.DurationType, Integer: int64(b.cooldown)})
+	}
+	return checked
+}
+
+// notice writes a breaker state change at the breaker level
+func (c *breakerCore) notice(ent zapcore.Entry, msg string, fields ...zapcore.Field) {
+	notice := zapcore.Entry{
+		Level:      c.breaker.level,
+		Time:       ent.Time,
+		LoggerName: ent.LoggerName,
+		Message:    msg,
+	}
+	if checked := c.Core.Check(notice, nil); checked != nil {
+		if err := writeChecked(checked, notice, fields); err != nil {
+			c.breaker.report(fmt.Errorf("logger: write breaker notice: %w", err))
+		}
+	}
+}
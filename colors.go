@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Color mode constants
+const (
+	ColorModeAuto   = "auto"   // colors console output outside production
+	ColorModeAlways = "always" // always colors console output
+	ColorModeNever  = "never"  // never colors output
+)
+
+// colorCodes maps color names to ANSI SGR codes
+var colorCodes = map[string]string{
+	"black":          "30",
+	"red":            "31",
+	"green":          "32",
+	"yellow":         "33",
+	"blue":           "34",
+	"magenta":        "35",
+	"cyan":           "36",
+	"white":          "37",
+	"gray":           "90",
+	"bright_red":     "91",
+	"bright_green":   "92",
+	"bright_yellow":  "93",
+	"bright_blue":    "94",
+	"bright_magenta": "95",
+	"bright_cyan":    "96",
+	"bright_white":   "97",
+	"bold":           "1",
+	"dim":            "2",
+}
+
+// defaultLevelColors mirrors zap's CapitalColorLevelEncoder palette
+var defaultLevelColors = map[zapcore.Level]string{
+	zapcore.DebugLevel:  "magenta",
+	zapcore.InfoLevel:   "blue",
+	zapcore.WarnLevel:   "yellow",
+	zapcore.ErrorLevel:  "red",
+	zapcore.DPanicLevel: "red",
+	zapcore.PanicLevel:  "red",
+	zapcore.FatalLevel:  "red",
+}
+
+// colorize wraps s in the ANSI sequence for the given color name or raw SGR code
+func colorize(color, s string) string {
+	if color == "" {
+		return s
+	}
+	code, ok := colorCodes[strings.ToLower(color)]
+	if !ok {
+		code = color // assume a raw SGR code such as "1;31"
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+// colorsEnabled reports whether console output should be colored
+func (c Config) colorsEnabled() bool {
+	if c.Encoding == EncodingJSON || c.Encoding == EncodingJSONPretty {
+		return false
+	}
+	switch c.Colors.Mode {
+	case ColorModeAlways:
+		return true
+	case ColorModeNever:
+		return false
+	default:
+		return !c.IsProduction()
+	}
+}
+
+// newColorLevelEncoder returns a capital level encoder using the configured per-level colors
+func newColorLevelEncoder(options ColorOptions) zapcore.LevelEncoder {
+	colors := make(map[zapcore.Level]string, len(defaultLevelColors))
+	for level, color := range defaultLevelColors {
+		colors[level] = color
+	}
+	for name, color := range options.Levels {
+		if level, err := zapcore.ParseLevel(name); err == nil {
+			colors[level] = color
+		}
+	}
+
+	return func(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		enc.AppendString(colorize(colors[l], l.CapitalString()))
+	}
+}
+
+// configureColors applies the configured color scheme to the encoder config
+func configureColors(config Config, encoderConfig *zapcore.EncoderConfig) {
+	if !config.colorsEnabled() {
+		if !config.IsProduction() {
+			encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		}
+		return
+	}
+
+	encoderConfig.EncodeLevel = newColorLevelEncoder(config.Colors)
+
+	if keyColor := config.Colors.Keys; keyColor != "" {
+		encoderConfig.EncodeName = func(name string, enc zapcore.PrimitiveArrayEncoder) {
+			enc.AppendString(colorize(keyColor, name))
+		}
+		fullPath := config.CallerFormat == CallerFormatFull
+		encoderConfig.EncodeCaller = func(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
+			path := caller.TrimmedPath()
+			if fullPath {
+				path = caller.FullPath()
+			}
+			enc.AppendString(colorize(keyColor, path))
+		}
+	}
+}
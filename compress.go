@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression algorithm constants
+const (
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+	CompressionNone = "none"
+)
+
+// compressionSuffixes maps the algorithms to the suffix of compressed files
+var compressionSuffixes = map[string]string{
+	CompressionGzip: ".gz",
+	CompressionZstd: ".zst",
+}
+
+// compression returns the algorithm compressing rotated files
+func (o FileOptions) compression() string {
+	switch algorithm := strings.ToLower(o.Compression); algorithm {
+	case "":
+		if o.Compress {
+			return CompressionGzip
+		}
+		return CompressionNone
+	case CompressionGzip, CompressionZstd:
+		return algorithm
+	default:
+		return CompressionNone
+	}
+}
+
+// isCompressed reports whether a rotated file is already compressed
+func isCompressed(path string) bool {
+	for _, suffix := range compressionSuffixes {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// pendingBackups lists the rotated backups of a log file neither compressed
+// nor encrypted yet, oldest first
+func pendingBackups(filename, active string) []string {
+	var backups []string
+	for _, backup := range rotatedBackups(filename, active) {
+		if !isCompressed(backup) && !isEncrypted(backup) {
+			backups = append(backups, backup)
+		}
+	}
+	return backups
+}
+
+// compressFile compresses a rotated file next to it at the given level,
+// zero being the default, and removes the original. It returns the path of
+// the compressed file.
+func compressFile(path, algorithm string, level int) (string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return "", err
+	}
+	dstPath := path + compressionSuffixes[algorithm]
+	dst, err := os.OpenFile(dstPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode())
+	if err != nil {
+		return "", err
+	}
+
+	if err := compressTo(dst, src, algorithm, level); err != nil {
+		dst.Close()
+		os.Remove(dstPath)
+		return "", err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(dstPath)
+		return "", err
+	}
+	// Keep the modification time, which retention goes by
+	os.Chtimes(dstPath, info.ModTime(), info.ModTime())
+	src.Close()
+	if err := os.Remove(path); err != nil {
+		return "", err
+	}
+	return dstPath, nil
+}
+
+// compressTo writes the compressed content of src to dst and syncs it
+func compressTo(dst *os.File, src io.Reader, algorithm string, level int) error {
+	var w io.WriteCloser
+	switch algorithm {
+	case CompressionZstd:
+		var opts []zstd.EOption
+		if level > 0 {
+			opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+		}
+		enc, err := zstd.NewWriter(dst, opts...)
+		if err != nil {
+			return err
+		}
+		w = enc
+	default:
+		if level <= 0 {
+			level = gzip.DefaultCompression
+		}
+		gz, err := gzip.NewWriterLevel(dst, level)
+		if err != nil {
+			return err
+		}
+		w = gz
+	}
+	if _, err := io.Copy(w, src); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return dst.Sync()
+}
@@ -2,6 +2,7 @@ package logger
 
 import (
 	"os"
+	"time"
 )
 
 // RotationMode defines how log files should be rotated
@@ -14,6 +15,8 @@ const (
 	RotationModeTime RotationMode = "time"
 	// RotationModeBoth rotates based on both size and time (whichever comes first)
 	RotationModeBoth RotationMode = "both"
+	// RotationModeNone never rotates, e.g. for audit logs rotated externally
+	RotationModeNone RotationMode = "none"
 )
 
 // TimeRotationInterval defines the time interval for rotation
@@ -50,15 +53,52 @@ const (
 
 // Encoding constants
 const (
-	EncodingJSON    = "json"
-	EncodingConsole = "console"
+	EncodingJSON       = "json"
+	EncodingJSONPretty = "json-pretty" // indented JSON for local development
+	EncodingConsole    = "console"
+)
+
+// Time encoding constants
+const (
+	TimeEncodingISO8601     = "iso8601"
+	TimeEncodingRFC3339     = "rfc3339"
+	TimeEncodingRFC3339Nano = "rfc3339nano"
+	TimeEncodingEpoch       = "epoch"        // float seconds since the Unix epoch
+	TimeEncodingEpochMillis = "epoch_millis" // float milliseconds since the Unix epoch
+	TimeEncodingEpochNanos  = "epoch_nanos"  // integer nanoseconds since the Unix epoch
+)
+
+// Caller format constants
+const (
+	CallerFormatShort = "short" // package/file.go:line
+	CallerFormatFull  = "full"  // /full/path/to/file.go:line
+	CallerFormatNone  = "none"  // caller is not recorded
+)
+
+// Fallback output constants
+const (
+	FallbackStderr = "stderr"
+	FallbackStdout = "stdout"
+	FallbackNone   = "none" // entries are dropped while the sink fails
 )
 
 // FileOptions holds file-specific logging options
 type FileOptions struct {
-	// Filename is the file to write logs to. If empty, logs will only go to stdout
+	// Filename is the file to write logs to. If empty, logs will only go to stdout.
+	// It may contain {hostname} and {pid}, so that replicas sharing a volume
+	// write distinct files, and {time}, where time-based rotation puts the
+	// period (default: before the extension). With size rotation {time} is
+	// the process start time.
 	Filename string `json:"filename" yaml:"filename"`
 
+	// Name identifies the file in Config.Sinks and metrics. The main file is
+	// always named "file"; additional files default to their Filename.
+	Name string `json:"name" yaml:"name"`
+
+	// Level is the minimum level written to the file. Empty writes every
+	// entry that passes Config.Level.
+	Level string `json:"level" yaml:"level"`
+
 	// MaxSize is the maximum size in megabytes of the log file before it gets rotated
 	MaxSize int `json:"max_size" yaml:"max_size"`
 
@@ -68,6 +108,11 @@ type FileOptions struct {
 	// MaxBackups is the maximum number of old log files to retain
 	MaxBackups int `json:"max_backups" yaml:"max_backups"`
 
+	// MaxTotalSize is the maximum size in megabytes of the log file and all
+	// its rotated backups, compressed or time-stamped. The oldest backups are
+	// removed when it is exceeded, regardless of MaxAge and MaxBackups.
+	MaxTotalSize int `json:"max_total_size" yaml:"max_total_size"`
+
 	// LocalTime determines if the time used for formatting the timestamps in
 	// backup files is the computer's local time. Default is UTC time.
 	LocalTime bool `json:"local_time" yaml:"local_time"`
@@ -75,9 +120,38 @@ type FileOptions struct {
 	// Compress determines if the rotated log files should be compressed using gzip
 	Compress bool `json:"compress" yaml:"compress"`
 
+	// Compression is the algorithm compressing rotated files: "gzip", "zstd"
+	// or "none". Empty uses gzip if Compress is set.
+	Compression string `json:"compression" yaml:"compression"`
+
+	// CompressionLevel is the compression level: 1 (fastest) to 9 (best)
+	// for gzip, 1 to 22 for zstd. Zero uses the algorithm's default.
+	CompressionLevel int `json:"compression_level" yaml:"compression_level"`
+
+	// CompressDelay keeps the newest CompressDelay rotated files
+	// uncompressed, compressing each one that many rotations later, so that
+	// a just-closed large file is not compressed right at the rotation.
+	CompressDelay int `json:"compress_delay" yaml:"compress_delay"`
+
+	// EncryptRecipients encrypts rotated files, after compression, to these
+	// age X25519 public keys ("age1..."). Encrypted files get the .age
+	// suffix and are decrypted with the age tool and a matching identity.
+	EncryptRecipients []string `json:"encrypt_recipients" yaml:"encrypt_recipients"`
+
 	// FileMode is the file mode to use when creating log files
 	FileMode os.FileMode `json:"file_mode" yaml:"file_mode"`
 
+	// ReopenInterval checks the file every interval and reopens it if an
+	// external tool such as logrotate renamed, deleted or truncated it
+	// (create and copytruncate modes). Zero disables the check.
+	ReopenInterval time.Duration `json:"reopen_interval" yaml:"reopen_interval"`
+
+	// Lock serializes writes of processes sharing the file with an advisory
+	// lock on <Filename>.lock, so that lines do not interleave and a process
+	// reopens the file rotated by another. It makes writes slower and is
+	// only supported on Unix.
+	Lock bool `json:"lock" yaml:"lock"`
+
 	// CreateDir determines if the directory should be created if it doesn't exist
 	CreateDir bool `json:"create_dir" yaml:"create_dir"`
 
@@ -91,9 +165,412 @@ type FileOptions struct {
 	// Default formats:
 	// - Hourly: "2006-01-02-15"
 	// - Daily: "2006-01-02"
-	// - Weekly: "2006-W01"
+	// - Weekly: "2006-01-02", the first day of the week
 	// - Monthly: "2006-01"
 	TimeRotationFormat string `json:"time_rotation_format" yaml:"time_rotation_format"`
+
+	// RotationTime is the wall-clock time, "HH:MM" in the time zone chosen by
+	// LocalTime, at which daily, weekly and monthly rotations happen instead
+	// of midnight. A file is named after the period it covers, e.g. with
+	// "02:00" app-2024-05-01.log holds May 1st 02:00 to May 2nd 02:00.
+	RotationTime string `json:"rotation_time" yaml:"rotation_time"`
+
+	// WeekStartDay is the first day of the weeks of weekly rotation, e.g.
+	// "sunday". Default is "monday". Weekly files are named after the first
+	// day of their week.
+	WeekStartDay string `json:"week_start_day" yaml:"week_start_day"`
+
+	// Symlink maintains Filename as a link to the current time-rotated file,
+	// giving tail -F and shipping agents a stable path. It is a symlink, or a
+	// hard link on Windows.
+	Symlink bool `json:"symlink" yaml:"symlink"`
+
+	// BufferSize buffers writes to the file up to this many bytes, reducing
+	// syscalls for high-throughput services. Zero disables buffering unless
+	// FlushInterval is set, in which case zap's default of 256 kB is used.
+	BufferSize int `json:"buffer_size" yaml:"buffer_size"`
+
+	// FlushInterval is how often buffered writes are flushed. Default is 30
+	// seconds. Buffers are also flushed on Sync and on entries above error.
+	FlushInterval time.Duration `json:"flush_interval" yaml:"flush_interval"`
+
+	// DiskFullPolicy handles writes failing because the disk is full: "drop"
+	// counts and drops entries, "stdout" writes them to stdout, and "purge"
+	// removes the oldest rotated backups to reclaim space. Empty treats it as
+	// any other write error.
+	DiskFullPolicy string `json:"disk_full_policy" yaml:"disk_full_policy"`
+
+	// OnRotate is called in the background with the path of each rotated
+	// file. With compression it is called once the compressed file is
+	// written, unless CompressDelay defers the compression.
+	OnRotate func(oldPath string) `json:"-" yaml:"-"`
+
+	// OnRotateCommand is a command run after each rotation, with the path of
+	// the rotated file appended as its last argument, e.g.
+	// ["/usr/local/bin/ship-log", "--bucket", "logs"]
+	OnRotateCommand []string `json:"on_rotate_command" yaml:"on_rotate_command"`
+
+	// Archive uploads rotated files to object storage
+	Archive ArchiveOptions `json:"archive" yaml:"archive"`
+}
+
+// ArchiveOptions holds options for uploading rotated files to object storage.
+// Archiving is enabled by setting Uploader, e.g. from the s3archive,
+// gcsarchive or azurearchive packages.
+type ArchiveOptions struct {
+	// Uploader stores the rotated files
+	Uploader Uploader `json:"-" yaml:"-"`
+
+	// KeyPrefix is prepended to the file name to build the object key. It may
+	// contain {host}, {date} (2006-01-02), {year}, {month}, {day} and {hour},
+	// taken from the rotation time.
+	KeyPrefix string `json:"key_prefix" yaml:"key_prefix"`
+
+	// DeleteLocal removes the local file once its upload is verified.
+	// Otherwise it is kept until MaxBackups, MaxAge or MaxTotalSize remove it.
+	DeleteLocal bool `json:"delete_local" yaml:"delete_local"`
+
+	// Retries is the number of upload attempts after the first fails.
+	// Default is 3.
+	Retries int `json:"retries" yaml:"retries"`
+
+	// Timeout bounds each upload attempt. Default is 5 minutes.
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+}
+
+// PrettyJSONOptions holds options for the json-pretty encoding
+type PrettyJSONOptions struct {
+	// Indent is the indentation used for nested lines. Default is two spaces.
+	Indent string `json:"indent" yaml:"indent"`
+
+	// SortKeys orders the keys of each entry alphabetically instead of
+	// keeping the encoder's natural order
+	SortKeys bool `json:"sort_keys" yaml:"sort_keys"`
+}
+
+// ColorOptions holds console color options
+type ColorOptions struct {
+	// Mode is auto, always, or never. Auto colors console output outside
+	// production. Colors are never applied to JSON encodings.
+	Mode string `json:"mode" yaml:"mode"`
+
+	// Levels overrides the color of individual levels, keyed by level name
+	// (e.g. {"info": "green"}). Values are color names or raw ANSI SGR codes.
+	Levels map[string]string `json:"levels" yaml:"levels"`
+
+	// Keys, when set, colors the logger name and caller columns
+	Keys string `json:"keys" yaml:"keys"`
+}
+
+// EnrichmentOptions selects host and process fields attached to every entry
+type EnrichmentOptions struct {
+	// Hostname adds the "hostname" field
+	Hostname bool `json:"hostname" yaml:"hostname"`
+
+	// PID adds the "pid" field
+	PID bool `json:"pid" yaml:"pid"`
+
+	// GoroutineID adds the "goroutine" field with the ID of the logging
+	// goroutine. This inspects the stack on every entry, so it has a cost.
+	GoroutineID bool `json:"goroutine_id" yaml:"goroutine_id"`
+}
+
+// RedactionOptions configures masking of sensitive fields
+type RedactionOptions struct {
+	// Enabled turns redaction on
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Keys are the field names to mask, matched case-insensitively.
+	// Empty means DefaultRedactKeys.
+	Keys []string `json:"keys" yaml:"keys"`
+
+	// Mask replaces the value of redacted fields, and of struct fields
+	// tagged log:"mask". Default is "***".
+	Mask string `json:"mask" yaml:"mask"`
+}
+
+// ScrubbingOptions configures regex scrubbing of PII in messages and the
+// string values of fields: strings, byte strings, Stringers, errors
+// (message and %+v rendering), and the strings inside objects, arrays and
+// reflected values. Objects, arrays and reflected values that contain a
+// match are written as their scrubbed reflected form. Inline fields such as
+// those of Lazy are not scrubbed.
+type ScrubbingOptions struct {
+	// Presets enables built-in patterns: credit_card, email, bearer_token, jwt
+	Presets []string `json:"presets" yaml:"presets"`
+
+	// Patterns are additional regular expressions to scrub
+	Patterns []string `json:"patterns" yaml:"patterns"`
+
+	// Replacement replaces each match. Default is "***".
+	Replacement string `json:"replacement" yaml:"replacement"`
+}
+
+// SinkOptions holds options for one output, keyed in Config.Sinks by the
+// output name ("stdout", "file", or the name of an additional file)
+type SinkOptions struct {
+	// Encoding overrides Config.Encoding for this output, e.g. colored
+	// console on stdout and JSON in the file
+	Encoding string `json:"encoding" yaml:"encoding"`
+
+	// Level is the minimum level written to this output, e.g. debug in the
+	// file and warn on a shipped stdout. Entries must also pass Config.Level
+	// and the named levels, so set those to the lowest output level.
+	Level string `json:"level" yaml:"level"`
+
+	// KeepFields, when set, drops every field not listed
+	KeepFields []string `json:"keep_fields" yaml:"keep_fields"`
+
+	// DropFields drops the listed fields
+	DropFields []string `json:"drop_fields" yaml:"drop_fields"`
+}
+
+// SamplingRate is the sampling rate of one level
+type SamplingRate struct {
+	// Initial is the number of entries logged per message and tick
+	Initial int `json:"initial" yaml:"initial"`
+
+	// Thereafter logs every Nth entry after Initial. Zero drops the rest.
+	Thereafter int `json:"thereafter" yaml:"thereafter"`
+}
+
+// SamplingOptions caps the volume of repeated entries. Entries are grouped by
+// level and message; within each Tick the first Initial entries of a group
+// are logged, then only every Thereafter-th one.
+type SamplingOptions struct {
+	// Enabled turns sampling on
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Initial is the number of entries logged per group and tick
+	Initial int `json:"initial" yaml:"initial"`
+
+	// Thereafter logs every Nth entry after Initial. Zero drops the rest.
+	Thereafter int `json:"thereafter" yaml:"thereafter"`
+
+	// Tick is the sampling interval. Default is one second.
+	Tick time.Duration `json:"tick" yaml:"tick"`
+
+	// Levels overrides Initial and Thereafter per level name, so debug and
+	// info can be sampled aggressively while warn is sampled lightly
+	Levels map[string]SamplingRate `json:"levels" yaml:"levels"`
+
+	// UnsampledLevel exempts this level and above from sampling, e.g. "error"
+	UnsampledLevel string `json:"unsampled_level" yaml:"unsampled_level"`
+}
+
+// RateLimitOptions throttles entries with a token bucket per key. The key is
+// the message, or the value of KeyField for entries that carry it.
+type RateLimitOptions struct {
+	// Enabled turns rate limiting on
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Rate is the number of entries per second allowed per key
+	Rate float64 `json:"rate" yaml:"rate"`
+
+	// Burst is the number of entries a key can log at once. Default is 1.
+	Burst int `json:"burst" yaml:"burst"`
+
+	// KeyField groups entries by the value of this field (e.g. "endpoint")
+	// instead of by message
+	KeyField string `json:"key_field" yaml:"key_field"`
+
+	// ReportInterval is how often a throttled key logs a "suppressed N
+	// entries" notice. Default is 10 seconds.
+	ReportInterval time.Duration `json:"report_interval" yaml:"report_interval"`
+
+	// UnlimitedLevel exempts this level and above from rate limiting.
+	// Default is error; "fatal" limits every level below fatal.
+	UnlimitedLevel string `json:"unlimited_level" yaml:"unlimited_level"`
+}
+
+// DedupOptions collapses consecutive identical entries into one entry with a
+// repeat_count field, so retry loops do not flood the disk
+type DedupOptions struct {
+	// Enabled turns deduplication on
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Interval is the longest time repeats are held before their count is
+	// flushed. Default is 5 seconds.
+	Interval time.Duration `json:"interval" yaml:"interval"`
+}
+
+// CircuitBreakerOptions protects the disk from runaway logging. When output
+// exceeds a threshold, entries below Level are dropped until Cooldown
+// elapses.
+type CircuitBreakerOptions struct {
+	// Enabled turns the circuit breaker on
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// MaxEntriesPerSecond opens the breaker above this many entries per
+	// second. Zero disables the check.
+	MaxEntriesPerSecond int `json:"max_entries_per_second" yaml:"max_entries_per_second"`
+
+	// MaxBytesPerSecond opens the breaker above this many bytes written per
+	// second. Zero disables the check.
+	MaxBytesPerSecond int64 `json:"max_bytes_per_second" yaml:"max_bytes_per_second"`
+
+	// Level is the effective level while the breaker is open. Default is warn.
+	Level string `json:"level" yaml:"level"`
+
+	// Cooldown is how long the breaker stays open. Default is 30 seconds.
+	Cooldown time.Duration `json:"cooldown" yaml:"cooldown"`
+}
+
+// AsyncOptions moves encoding and writing to a background goroutine fed by
+// a bounded queue. Call Close before exit to write the queued entries. Lazy
+// fields, marshalers and Stringers are evaluated before queueing; values
+// logged with Any or Reflect are read by the worker and must not be changed
+// after the logging call.
+type AsyncOptions struct {
+	// Enabled turns asynchronous logging on
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// QueueSize is the number of entries queued before logging calls block.
+	// Default is 1024.
+	QueueSize int `json:"queue_size" yaml:"queue_size"`
+
+	// NonBlocking drops entries instead of blocking when the queue stays
+	// full for longer than MaxWait, e.g. because a sink is slow
+	NonBlocking bool `json:"non_blocking" yaml:"non_blocking"`
+
+	// MaxWait is the longest a logging call waits for queue space in
+	// non-blocking mode. Zero drops entries as soon as the queue is full.
+	MaxWait time.Duration `json:"max_wait" yaml:"max_wait"`
+
+	// ReportInterval is how often the number of dropped entries is reported
+	// to the internal error hook. Default is 10 seconds.
+	ReportInterval time.Duration `json:"report_interval" yaml:"report_interval"`
+}
+
+// BacktraceOptions keeps the most recent entries below the configured level
+// in memory and writes them when an entry at the trigger level occurs, so
+// errors come with debug context without logging at debug permanently
+type BacktraceOptions struct {
+	// Enabled turns the backtrace buffer on
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Size is the number of entries kept. Default is 100.
+	Size int `json:"size" yaml:"size"`
+
+	// Level triggers writing the buffer. Default is error.
+	Level string `json:"level" yaml:"level"`
+}
+
+// AuditOptions configures the audit log written by Audit
+type AuditOptions struct {
+	// File is the audit log file, with its own rotation and retention.
+	// Without a filename, audit events are written to the application log.
+	File FileOptions `json:"file" yaml:"file"`
+
+	// Encoding of the audit log. Default is json.
+	Encoding string `json:"encoding" yaml:"encoding"`
+}
+
+// RouteRule sends the entries whose field matches to a sink. A sink named
+// by rules only receives the entries matching one of them, e.g. a
+// "billing" file receiving the entries with component=billing while every
+// entry still goes to the other outputs. Equals, Prefix and Regex must all
+// match when set; with none set, the field only has to be present.
+type RouteRule struct {
+	// Field is the field to match, in the entry or added through With
+	Field string `json:"field" yaml:"field"`
+
+	// Equals matches the exact field value
+	Equals string `json:"equals" yaml:"equals"`
+
+	// Prefix matches the start of the field value
+	Prefix string `json:"prefix" yaml:"prefix"`
+
+	// Regex matches the field value against a regular expression
+	Regex string `json:"regex" yaml:"regex"`
+
+	// Sink is the name of the target output: a Files entry, "file",
+	// "stdout", "stderr" or an output path
+	Sink string `json:"sink" yaml:"sink"`
+}
+
+// DropFilter drops the entries matching all of its conditions, e.g. a
+// harmless but noisy warning of a dependency. At least one condition must
+// be set.
+type DropFilter struct {
+	// Message is a regular expression matched against the message
+	Message string `json:"message" yaml:"message"`
+
+	// Logger is the logger name; it also matches the named children, e.g.
+	// "kafka" matches "kafka.consumer"
+	Logger string `json:"logger" yaml:"logger"`
+
+	// Level is the exact level of the entry
+	Level string `json:"level" yaml:"level"`
+
+	// Field is a field the entry must carry, in its own fields or added
+	// through With
+	Field string `json:"field" yaml:"field"`
+
+	// FieldValue is a regular expression matched against the value of
+	// Field. Empty matches any value.
+	FieldValue string `json:"field_value" yaml:"field_value"`
+}
+
+// LevelOverride changes the level of the entries matching all of its
+// conditions, e.g. to write "context canceled" errors at debug or promote a
+// specific warning to error. At least one condition must be set.
+type LevelOverride struct {
+	// Message is a regular expression matched against the message
+	Message string `json:"message" yaml:"message"`
+
+	// Logger is the logger name; it also matches the named children
+	Logger string `json:"logger" yaml:"logger"`
+
+	// Level is the exact level the entry is logged at
+	Level string `json:"level" yaml:"level"`
+
+	// Field is a field the entry must carry, in its own fields or added
+	// through With
+	Field string `json:"field" yaml:"field"`
+
+	// FieldValue is a regular expression matched against the value of
+	// Field. Empty matches any value.
+	FieldValue string `json:"field_value" yaml:"field_value"`
+
+	// To is the level the entry is written at, at most error. A demoted
+	// entry is dropped if To is disabled for its logger. Only entries
+	// enabled at their own level reach the overrides, so an entry cannot be
+	// promoted from a disabled level. An entry promoted to error through a
+	// Field condition, which is only matched when the entry is written,
+	// gets no stack trace.
+	To string `json:"to" yaml:"to"`
+}
+
+// TenantOptions routes the entries of each tenant of a multi-tenant
+// service to its own log file
+type TenantOptions struct {
+	// Field is the field holding the tenant, e.g. "tenant_id", in the entry
+	// or added through With. Empty disables the routing.
+	Field string `json:"field" yaml:"field"`
+
+	// File holds the rotation settings shared by the tenant files. Its
+	// Filename must contain {tenant}, e.g. "logs/tenants/{tenant}.log".
+	File FileOptions `json:"file" yaml:"file"`
+
+	// Encoding of the tenant files. Empty uses Encoding.
+	Encoding string `json:"encoding" yaml:"encoding"`
+
+	// MaxOpen is the number of tenant files kept open; the least recently
+	// used one is closed beyond it. Default is 100.
+	MaxOpen int `json:"max_open" yaml:"max_open"`
+
+	// Exclusive writes the entries of a tenant to its file only, not to
+	// the other outputs
+	Exclusive bool `json:"exclusive" yaml:"exclusive"`
+}
+
+// SecurityOptions configures the security events written by the Sec
+// methods
+type SecurityOptions struct {
+	// RouteToAudit writes security events to the audit file instead of the
+	// application log
+	RouteToAudit bool `json:"route_to_audit" yaml:"route_to_audit"`
 }
 
 // Config holds logger configuration
@@ -103,6 +580,140 @@ type Config struct {
 	OutputPaths []string    `json:"output_paths" yaml:"output_paths"`
 	Encoding    string      `json:"encoding" yaml:"encoding"`
 	FileOptions FileOptions `json:"file_options" yaml:"file_options"`
+
+	// Files are additional log files, each with its own level and rotation,
+	// e.g. errors.log at error alongside the main file
+	Files []FileOptions `json:"files" yaml:"files"`
+
+	// Audit holds the dedicated audit log
+	Audit AuditOptions `json:"audit" yaml:"audit"`
+
+	// Security holds the routing of security events
+	Security SecurityOptions `json:"security" yaml:"security"`
+
+	// Routes restrict sinks to the entries matching field rules
+	Routes []RouteRule `json:"routes" yaml:"routes"`
+
+	// Tenants holds the routing of entries to per-tenant files
+	Tenants TenantOptions `json:"tenants" yaml:"tenants"`
+
+	// Sinks holds per-output options keyed by output name ("stdout", "file")
+	Sinks map[string]SinkOptions `json:"sinks" yaml:"sinks"`
+
+	// InitialFields are added to every entry, e.g. service name, version,
+	// git SHA, or deployment region
+	InitialFields map[string]any `json:"initial_fields" yaml:"initial_fields"`
+
+	// EnvFields maps environment variable names to field keys. Each variable
+	// that is set is added to every entry, e.g. {"K8S_CLUSTER": "cluster"}.
+	EnvFields map[string]string `json:"env_fields" yaml:"env_fields"`
+
+	// Enrichment attaches host and process fields to every entry
+	Enrichment EnrichmentOptions `json:"enrichment" yaml:"enrichment"`
+
+	// Redaction masks sensitive fields before they are encoded
+	Redaction RedactionOptions `json:"redaction" yaml:"redaction"`
+
+	// Scrubbing replaces PII patterns in messages and string fields
+	Scrubbing ScrubbingOptions `json:"scrubbing" yaml:"scrubbing"`
+
+	// Sampling caps the volume of repeated entries
+	Sampling SamplingOptions `json:"sampling" yaml:"sampling"`
+
+	// RateLimit throttles entries sharing a message or key field
+	RateLimit RateLimitOptions `json:"rate_limit" yaml:"rate_limit"`
+
+	// Dedup collapses consecutive identical entries
+	Dedup DedupOptions `json:"dedup" yaml:"dedup"`
+
+	// CircuitBreaker raises the level when log volume runs away
+	CircuitBreaker CircuitBreakerOptions `json:"circuit_breaker" yaml:"circuit_breaker"`
+
+	// Async encodes and writes entries on a background goroutine
+	Async AsyncOptions `json:"async" yaml:"async"`
+
+	// Backtrace dumps recent entries below the level when an error occurs
+	Backtrace BacktraceOptions `json:"backtrace" yaml:"backtrace"`
+
+	// FallbackOutput receives entries while the file sink fails to write:
+	// "stderr" (the default), "stdout", or "none" to drop them
+	FallbackOutput string `json:"fallback_output" yaml:"fallback_output"`
+
+	// Levels sets the level of named loggers, keyed by logger name
+	// (e.g. {"http": "debug", "db": "warn"}). Names are hierarchical, so
+	// "server.http" also applies to "server.http.router". Level is used
+	// for loggers without a matching entry.
+	Levels map[string]string `json:"levels" yaml:"levels"`
+
+	// PrettyJSON holds options applied when Encoding is json-pretty
+	PrettyJSON PrettyJSONOptions `json:"pretty_json" yaml:"pretty_json"`
+
+	// Colors holds the console color scheme
+	Colors ColorOptions `json:"colors" yaml:"colors"`
+
+	// Timezone is the IANA time zone name (e.g. "UTC", "Asia/Ho_Chi_Minh") used
+	// when encoding entry timestamps. Empty means the process local zone.
+	// This is independent of FileOptions.LocalTime, which only affects rotation.
+	Timezone string `json:"timezone" yaml:"timezone"`
+
+	// TimeEncoding selects how entry timestamps are encoded. Empty means iso8601.
+	// The epoch encodings emit numbers and ignore Timezone.
+	TimeEncoding string `json:"time_encoding" yaml:"time_encoding"`
+
+	// CallerFormat controls how the caller is encoded (short, full, or none).
+	// Empty means short.
+	CallerFormat string `json:"caller_format" yaml:"caller_format"`
+
+	// CallerFunction adds the calling function name to each entry
+	CallerFunction bool `json:"caller_function" yaml:"caller_function"`
+
+	// DisableCaller stops annotating entries with the calling file and line.
+	// It is equivalent to CallerFormat "none".
+	DisableCaller bool `json:"disable_caller" yaml:"disable_caller"`
+
+	// DisableStacktrace stops capturing stack traces for error and above entries
+	DisableStacktrace bool `json:"disable_stacktrace" yaml:"disable_stacktrace"`
+
+	// OnInternalError receives errors of the logger itself, such as failing
+	// sinks. Default writes them to stderr.
+	OnInternalError func(error) `json:"-" yaml:"-"`
+
+	// Metrics receives entry, write and drop metrics, e.g. a
+	// prommetrics.Recorder
+	Metrics MetricsRecorder `json:"-" yaml:"-"`
+
+	// Hooks can change or drop entries before they are encoded
+	Hooks []Hook `json:"-" yaml:"-"`
+
+	// LevelOverrides change the level of matching entries; the first
+	// matching override applies
+	LevelOverrides []LevelOverride `json:"level_overrides" yaml:"level_overrides"`
+
+	// Drop filters drop matching entries after the hooks and before the
+	// other processing stages
+	Drop []DropFilter `json:"drop" yaml:"drop"`
+
+	// RepanicOnRecover makes Recover panic again with the recovered value
+	// after logging it, e.g. to keep crashing in development
+	RepanicOnRecover bool `json:"repanic_on_recover" yaml:"repanic_on_recover"`
+
+	// ExitFunc is called with exit code 1 after a fatal entry is written,
+	// instead of os.Exit. It can run shutdown hooks before exiting, or let
+	// tests assert fatal paths; Fatal returns if it does not exit.
+	ExitFunc func(code int) `json:"-" yaml:"-"`
+
+	// StrictValidation makes the constructors fail with the errors of
+	// ValidateStrict instead of falling back to defaults for invalid
+	// settings, e.g. to catch typos in deployment manifests
+	StrictValidation bool `json:"strict_validation" yaml:"strict_validation"`
+
+	// Expvar publishes logger statistics (level, entries per level, drops,
+	// sink errors, rotations) as an expvar map under this name, e.g. "logger"
+	Expvar string `json:"expvar" yaml:"expvar"`
+
+	// envErrors are the environment variables ConfigFromEnv could not
+	// parse and ignored
+	envErrors []error
 }
 
 // DefaultFileOptions returns default file options
@@ -122,6 +733,15 @@ func DefaultFileOptions() FileOptions {
 	}
 }
 
+// DefaultAuditFileOptions returns default audit file options: rotated files
+// are compressed but never deleted
+func DefaultAuditFileOptions() FileOptions {
+	options := DefaultFileOptions()
+	options.MaxAge = 0
+	options.MaxBackups = 0
+	return options
+}
+
 // DefaultConfig returns default logger configuration
 func DefaultConfig() Config {
 	return Config{
@@ -130,6 +750,8 @@ func DefaultConfig() Config {
 		OutputPaths: []string{"stdout"},
 		Encoding:    "console",
 		FileOptions: DefaultFileOptions(),
+		Audit:       AuditOptions{File: DefaultAuditFileOptions()},
+		Tenants:     TenantOptions{File: DefaultFileOptions()},
 	}
 }
 
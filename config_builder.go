@@ -1,8 +1,10 @@
 package logger
 
 import (
+	"errors"
 	"os"
 	"strings"
+	"time"
 )
 
 // IsProduction checks if the environment is production
@@ -20,56 +22,261 @@ func (c Config) IsTest() bool {
 	return c.Environment == EnvTest
 }
 
-// Validate validates the configuration
+// callerEnabled reports whether entries should be annotated with the caller
+func (c Config) callerEnabled() bool {
+	return !c.DisableCaller && c.CallerFormat != CallerFormatNone
+}
+
+// Validate reports the invalid settings of the configuration as one error,
+// the errors of ValidateStrict joined. It returns nil if the configuration
+// is valid.
 func (c Config) Validate() error {
-	// Validate log level
-	validLevels := map[string]bool{
-		LevelDebug: true,
-		LevelInfo:  true,
-		LevelWarn:  true,
-		LevelError: true,
-		LevelFatal: true,
-		LevelPanic: true,
-	}
-	if !validLevels[c.Level] {
-		c.Level = LevelInfo
-	}
+	return errors.Join(c.ValidateStrict()...)
+}
+
+// WithStrictValidation makes the constructors and Initialize fail with the
+// errors of ValidateStrict instead of falling back to defaults
+func (c Config) WithStrictValidation(strict bool) Config {
+	c.StrictValidation = strict
+	return c
+}
+
+// WithLevel sets the log level
+func (c Config) WithLevel(level string) Config {
+	c.Level = strings.ToLower(level)
+	return c
+}
 
-	// Validate environment
-	validEnvs := map[string]bool{
-		EnvDevelopment: true,
-		EnvStaging:     true,
-		EnvProduction:  true,
-		EnvTest:        true,
+// WithInitialField adds a field included in every entry
+func (c Config) WithInitialField(key string, value any) Config {
+	fields := make(map[string]any, len(c.InitialFields)+1)
+	for k, v := range c.InitialFields {
+		fields[k] = v
 	}
-	if !validEnvs[c.Environment] {
-		c.Environment = EnvDevelopment
+	fields[key] = value
+	c.InitialFields = fields
+	return c
+}
+
+// WithInitialFields adds fields included in every entry
+func (c Config) WithInitialFields(fields map[string]any) Config {
+	for key, value := range fields {
+		c = c.WithInitialField(key, value)
 	}
+	return c
+}
 
-	// Validate encoding
-	validEncodings := map[string]bool{
-		EncodingJSON:    true,
-		EncodingConsole: true,
+// WithEnvField adds the value of an environment variable to every entry under key
+func (c Config) WithEnvField(envVar, key string) Config {
+	fields := make(map[string]string, len(c.EnvFields)+1)
+	for k, v := range c.EnvFields {
+		fields[k] = v
 	}
-	if !validEncodings[c.Encoding] {
-		if c.IsProduction() {
-			c.Encoding = EncodingJSON
-		} else {
-			c.Encoding = EncodingConsole
-		}
+	fields[envVar] = key
+	c.EnvFields = fields
+	return c
+}
+
+// WithService adds the standard service and version fields to every entry
+func (c Config) WithService(name, version string) Config {
+	return c.WithInitialField("service", name).WithInitialField("version", version)
+}
+
+// WithHostEnrichment adds hostname and PID fields to every entry, and the
+// goroutine ID when goroutineID is true
+func (c Config) WithHostEnrichment(goroutineID bool) Config {
+	c.Enrichment.Hostname = true
+	c.Enrichment.PID = true
+	c.Enrichment.GoroutineID = goroutineID
+	return c
+}
+
+// WithRedaction enables masking of the given field names. With no keys,
+// DefaultRedactKeys are used.
+func (c Config) WithRedaction(keys ...string) Config {
+	c.Redaction.Enabled = true
+	c.Redaction.Keys = keys
+	return c
+}
+
+// WithRedactionMask sets the replacement for redacted values
+func (c Config) WithRedactionMask(mask string) Config {
+	c.Redaction.Mask = mask
+	return c
+}
+
+// WithScrubbingPresets enables built-in PII scrubbing patterns
+func (c Config) WithScrubbingPresets(presets ...string) Config {
+	c.Scrubbing.Presets = append(append([]string(nil), c.Scrubbing.Presets...), presets...)
+	return c
+}
+
+// WithScrubbingPatterns adds custom regular expressions to scrub
+func (c Config) WithScrubbingPatterns(patterns ...string) Config {
+	c.Scrubbing.Patterns = append(append([]string(nil), c.Scrubbing.Patterns...), patterns...)
+	return c
+}
+
+// WithSampling enables sampling of repeated entries
+func (c Config) WithSampling(initial, thereafter int, tick time.Duration) Config {
+	c.Sampling.Enabled = true
+	c.Sampling.Initial = initial
+	c.Sampling.Thereafter = thereafter
+	c.Sampling.Tick = tick
+	return c
+}
+
+// WithLevelSampling sets the sampling rate of one level
+func (c Config) WithLevelSampling(level string, initial, thereafter int) Config {
+	levels := make(map[string]SamplingRate, len(c.Sampling.Levels)+1)
+	for k, v := range c.Sampling.Levels {
+		levels[k] = v
 	}
+	levels[strings.ToLower(level)] = SamplingRate{Initial: initial, Thereafter: thereafter}
+	c.Sampling.Levels = levels
+	return c
+}
+
+// WithUnsampledLevel exempts the given level and above from sampling
+func (c Config) WithUnsampledLevel(level string) Config {
+	c.Sampling.UnsampledLevel = strings.ToLower(level)
+	return c
+}
+
+// WithRateLimit throttles entries sharing a message to perKey entries per
+// second, allowing bursts of up to burst entries
+func (c Config) WithRateLimit(perKey float64, burst int) Config {
+	c.RateLimit.Enabled = true
+	c.RateLimit.Rate = perKey
+	c.RateLimit.Burst = burst
+	return c
+}
+
+// WithRateLimitKey groups rate limited entries by the value of a field
+// instead of by message
+func (c Config) WithRateLimitKey(field string) Config {
+	c.RateLimit.KeyField = field
+	return c
+}
+
+// WithRateLimitUnlimitedLevel exempts level and above from rate limiting
+// instead of error and above
+func (c Config) WithRateLimitUnlimitedLevel(level string) Config {
+	c.RateLimit.UnlimitedLevel = strings.ToLower(level)
+	return c
+}
+
+// WithDedup collapses consecutive identical entries, flushing the repeat
+// count at least every interval
+func (c Config) WithDedup(interval time.Duration) Config {
+	c.Dedup = DedupOptions{Enabled: true, Interval: interval}
+	return c
+}
 
-	// Validate output paths
-	if len(c.OutputPaths) == 0 {
-		c.OutputPaths = []string{"stdout"}
+// WithCircuitBreaker drops entries below level for cooldown whenever output
+// exceeds maxEntries entries or maxBytes bytes per second. A zero threshold
+// is not checked.
+func (c Config) WithCircuitBreaker(maxEntries int, maxBytes int64, level string, cooldown time.Duration) Config {
+	c.CircuitBreaker = CircuitBreakerOptions{
+		Enabled:             true,
+		MaxEntriesPerSecond: maxEntries,
+		MaxBytesPerSecond:   maxBytes,
+		Level:               strings.ToLower(level),
+		Cooldown:            cooldown,
 	}
+	return c
+}
 
-	return nil
+// WithAsync encodes and writes entries on a background goroutine with a
+// queue of queueSize entries
+func (c Config) WithAsync(queueSize int) Config {
+	c.Async = AsyncOptions{Enabled: true, QueueSize: queueSize}
+	return c
 }
 
-// WithLevel sets the log level
-func (c Config) WithLevel(level string) Config {
-	c.Level = strings.ToLower(level)
+// WithBacktrace keeps the last size entries below the configured level and
+// writes them before the next entry at triggerLevel or above
+func (c Config) WithBacktrace(size int, triggerLevel string) Config {
+	c.Backtrace = BacktraceOptions{Enabled: true, Size: size, Level: strings.ToLower(triggerLevel)}
+	return c
+}
+
+// WithFallbackOutput sets where entries go while the file sink fails to
+// write: "stderr", "stdout", or "none"
+func (c Config) WithFallbackOutput(output string) Config {
+	c.FallbackOutput = strings.ToLower(output)
+	return c
+}
+
+// WithInternalErrorHandler sets the function receiving the logger's own
+// errors, such as failing sinks
+func (c Config) WithInternalErrorHandler(handler func(error)) Config {
+	c.OnInternalError = handler
+	return c
+}
+
+// WithNonBlocking enables async logging in which logging calls wait at most
+// maxWait for queue space; entries that do not fit are dropped and counted
+func (c Config) WithNonBlocking(maxWait time.Duration) Config {
+	c.Async.Enabled = true
+	c.Async.NonBlocking = true
+	c.Async.MaxWait = maxWait
+	return c
+}
+
+// WithMetrics records entry, write and drop metrics with the given recorder
+func (c Config) WithMetrics(recorder MetricsRecorder) Config {
+	c.Metrics = recorder
+	return c
+}
+
+// WithExpvar publishes logger statistics on /debug/vars under name
+func (c Config) WithExpvar(name string) Config {
+	c.Expvar = name
+	return c
+}
+
+// WithHook adds a hook that can change or drop entries before they are
+// encoded
+func (c Config) WithHook(hook Hook) Config {
+	c.Hooks = append(c.Hooks[:len(c.Hooks):len(c.Hooks)], hook)
+	return c
+}
+
+// WithDropFilter adds a filter dropping the entries matching all of its
+// conditions
+func (c Config) WithDropFilter(filter DropFilter) Config {
+	c.Drop = append(c.Drop[:len(c.Drop):len(c.Drop)], filter)
+	return c
+}
+
+// WithLevelOverride adds an override changing the level of the entries
+// matching all of its conditions
+func (c Config) WithLevelOverride(override LevelOverride) Config {
+	c.LevelOverrides = append(c.LevelOverrides[:len(c.LevelOverrides):len(c.LevelOverrides)], override)
+	return c
+}
+
+// WithExitFunc replaces os.Exit after fatal entries
+func (c Config) WithExitFunc(exit func(code int)) Config {
+	c.ExitFunc = exit
+	return c
+}
+
+// WithRepanicOnRecover makes Recover panic again after logging
+func (c Config) WithRepanicOnRecover(enabled bool) Config {
+	c.RepanicOnRecover = enabled
+	return c
+}
+
+// WithNamedLevel sets the level of a named logger
+func (c Config) WithNamedLevel(name, level string) Config {
+	levels := make(map[string]string, len(c.Levels)+1)
+	for k, v := range c.Levels {
+		levels[k] = v
+	}
+	levels[name] = strings.ToLower(level)
+	c.Levels = levels
 	return c
 }
 
@@ -85,6 +292,73 @@ func (c Config) WithEncoding(encoding string) Config {
 	return c
 }
 
+// WithPrettyJSON enables the json-pretty encoding with the given indent and key ordering
+func (c Config) WithPrettyJSON(indent string, sortKeys bool) Config {
+	c.Encoding = EncodingJSONPretty
+	c.PrettyJSON.Indent = indent
+	c.PrettyJSON.SortKeys = sortKeys
+	return c
+}
+
+// WithColorMode sets when console output is colored (auto, always, or never)
+func (c Config) WithColorMode(mode string) Config {
+	c.Colors.Mode = strings.ToLower(mode)
+	return c
+}
+
+// WithLevelColor sets the console color for a level
+func (c Config) WithLevelColor(level, color string) Config {
+	levels := make(map[string]string, len(c.Colors.Levels)+1)
+	for k, v := range c.Colors.Levels {
+		levels[k] = v
+	}
+	levels[strings.ToLower(level)] = color
+	c.Colors.Levels = levels
+	return c
+}
+
+// WithKeyColor sets the console color for the logger name and caller columns
+func (c Config) WithKeyColor(color string) Config {
+	c.Colors.Keys = color
+	return c
+}
+
+// WithTimezone sets the IANA time zone used for entry timestamps
+func (c Config) WithTimezone(tz string) Config {
+	c.Timezone = tz
+	return c
+}
+
+// WithTimeEncoding sets the timestamp encoding (iso8601, rfc3339, rfc3339nano, epoch, epoch_millis, epoch_nanos)
+func (c Config) WithTimeEncoding(encoding string) Config {
+	c.TimeEncoding = strings.ToLower(encoding)
+	return c
+}
+
+// WithCallerFormat sets how the caller is encoded (short, full, or none)
+func (c Config) WithCallerFormat(format string) Config {
+	c.CallerFormat = strings.ToLower(format)
+	return c
+}
+
+// WithCallerFunction enables or disables the calling function name in entries
+func (c Config) WithCallerFunction(enabled bool) Config {
+	c.CallerFunction = enabled
+	return c
+}
+
+// WithDisableCaller enables or disables caller annotation
+func (c Config) WithDisableCaller(disable bool) Config {
+	c.DisableCaller = disable
+	return c
+}
+
+// WithDisableStacktrace enables or disables stack trace capture
+func (c Config) WithDisableStacktrace(disable bool) Config {
+	c.DisableStacktrace = disable
+	return c
+}
+
 // WithOutputPaths sets the output paths
 func (c Config) WithOutputPaths(paths ...string) Config {
 	c.OutputPaths = paths
@@ -97,12 +371,98 @@ func (c Config) AddOutputPath(path string) Config {
 	return c
 }
 
+// WithAuditFile writes audit events to a dedicated file
+func (c Config) WithAuditFile(filename string) Config {
+	c.Audit.File.Filename = filename
+	return c
+}
+
+// WithAuditOptions sets the audit log options
+func (c Config) WithAuditOptions(options AuditOptions) Config {
+	c.Audit = options
+	return c
+}
+
+// WithSecurityEventsToAudit writes security events to the audit file
+// instead of the application log
+func (c Config) WithSecurityEventsToAudit() Config {
+	c.Security.RouteToAudit = true
+	return c
+}
+
+// WithRoute adds a rule sending the entries whose field matches to a sink,
+// which then only receives matching entries
+func (c Config) WithRoute(rule RouteRule) Config {
+	c.Routes = append(c.Routes[:len(c.Routes):len(c.Routes)], rule)
+	return c
+}
+
+// WithTenantFiles routes the entries carrying field to one file per
+// tenant. filename must contain {tenant}, e.g. "logs/tenants/{tenant}.log";
+// the tenant files share the rotation settings of TenantOptions.File.
+func (c Config) WithTenantFiles(field, filename string) Config {
+	c.Tenants.Field = field
+	c.Tenants.File.Filename = filename
+	return c
+}
+
+// WithTenantOptions sets the per-tenant file routing
+func (c Config) WithTenantOptions(options TenantOptions) Config {
+	c.Tenants = options
+	return c
+}
+
+// AddFile adds a log file with its own level and rotation options
+func (c Config) AddFile(options FileOptions) Config {
+	c.Files = append(c.Files[:len(c.Files):len(c.Files)], options)
+	return c
+}
+
 // WithFileOutput sets file output options
 func (c Config) WithFileOutput(filename string) Config {
 	c.FileOptions.Filename = filename
 	return c
 }
 
+// WithSinkOptions sets the options of an output ("stdout" or "file")
+func (c Config) WithSinkOptions(name string, options SinkOptions) Config {
+	sinks := make(map[string]SinkOptions, len(c.Sinks)+1)
+	for k, v := range c.Sinks {
+		sinks[k] = v
+	}
+	sinks[name] = options
+	c.Sinks = sinks
+	return c
+}
+
+// WithSinkEncoding sets the encoding of one output
+func (c Config) WithSinkEncoding(name, encoding string) Config {
+	options := c.Sinks[name]
+	options.Encoding = strings.ToLower(encoding)
+	return c.WithSinkOptions(name, options)
+}
+
+// WithSinkLevel sets the minimum level of one output
+func (c Config) WithSinkLevel(name, level string) Config {
+	options := c.Sinks[name]
+	options.Level = strings.ToLower(level)
+	return c.WithSinkOptions(name, options)
+}
+
+// WithSinkDropFields drops the given fields from one output
+func (c Config) WithSinkDropFields(name string, fields ...string) Config {
+	options := c.Sinks[name]
+	options.DropFields = append(append([]string(nil), options.DropFields...), fields...)
+	return c.WithSinkOptions(name, options)
+}
+
+// WithSinkKeepFields keeps only the given fields in one output
+func (c Config) WithSinkKeepFields(name string, fields ...string) Config {
+	options := c.Sinks[name]
+	options.KeepFields = append(append([]string(nil), options.KeepFields...), fields...)
+	return c.WithSinkOptions(name, options)
+}
+
 // WithFileRotation sets file rotation options
 func (c Config) WithFileRotation(maxSize, maxAge, maxBackups int) Config {
 	c.FileOptions.MaxSize = maxSize
@@ -111,18 +471,101 @@ func (c Config) WithFileRotation(maxSize, maxAge, maxBackups int) Config {
 	return c
 }
 
+// WithMaxTotalSize limits the log file and its rotated backups to maxTotalSize
+// megabytes, removing the oldest backups first
+func (c Config) WithMaxTotalSize(maxTotalSize int) Config {
+	c.FileOptions.MaxTotalSize = maxTotalSize
+	return c
+}
+
+// WithOnRotate calls fn with the path of each rotated file
+func (c Config) WithOnRotate(fn func(oldPath string)) Config {
+	c.FileOptions.OnRotate = fn
+	return c
+}
+
+// WithOnRotateCommand runs a command after each rotation, with the path of the
+// rotated file appended as its last argument
+func (c Config) WithOnRotateCommand(command ...string) Config {
+	c.FileOptions.OnRotateCommand = append([]string(nil), command...)
+	return c
+}
+
+// WithArchive uploads rotated files with uploader under keyPrefix, removing
+// the local copies once uploaded if deleteLocal is set
+func (c Config) WithArchive(uploader Uploader, keyPrefix string, deleteLocal bool) Config {
+	c.FileOptions.Archive.Uploader = uploader
+	c.FileOptions.Archive.KeyPrefix = keyPrefix
+	c.FileOptions.Archive.DeleteLocal = deleteLocal
+	return c
+}
+
 // WithFileCompression enables or disables file compression
 func (c Config) WithFileCompression(compress bool) Config {
 	c.FileOptions.Compress = compress
 	return c
 }
 
+// WithFileBuffer buffers file writes up to size bytes, flushing them at
+// least every flushInterval
+func (c Config) WithFileBuffer(size int, flushInterval time.Duration) Config {
+	c.FileOptions.BufferSize = size
+	c.FileOptions.FlushInterval = flushInterval
+	return c
+}
+
+// WithCompression sets the algorithm compressing rotated files: "gzip",
+// "zstd" or "none"
+func (c Config) WithCompression(algorithm string) Config {
+	c.FileOptions.Compression = strings.ToLower(algorithm)
+	return c
+}
+
+// WithCompressionLevel sets the compression level of rotated files
+func (c Config) WithCompressionLevel(level int) Config {
+	c.FileOptions.CompressionLevel = level
+	return c
+}
+
+// WithCompressDelay keeps the newest rotations rotated files uncompressed
+func (c Config) WithCompressDelay(rotations int) Config {
+	c.FileOptions.CompressDelay = rotations
+	return c
+}
+
+// WithEncryption encrypts rotated files to the given age X25519 public keys
+func (c Config) WithEncryption(recipients ...string) Config {
+	c.FileOptions.EncryptRecipients = append([]string(nil), recipients...)
+	return c
+}
+
+// WithDiskFullPolicy sets how the file sink handles a full disk: "drop",
+// "stdout", or "purge"
+func (c Config) WithDiskFullPolicy(policy string) Config {
+	c.FileOptions.DiskFullPolicy = strings.ToLower(policy)
+	return c
+}
+
 // WithLocalTime enables or disables local time for file timestamps
 func (c Config) WithLocalTime(localTime bool) Config {
 	c.FileOptions.LocalTime = localTime
 	return c
 }
 
+// WithReopenCheck reopens the file when an external tool rotated it,
+// checking every interval
+func (c Config) WithReopenCheck(interval time.Duration) Config {
+	c.FileOptions.ReopenInterval = interval
+	return c
+}
+
+// WithFileLock enables or disables locking the file for writers in several
+// processes
+func (c Config) WithFileLock(lock bool) Config {
+	c.FileOptions.Lock = lock
+	return c
+}
+
 // WithCreateDir enables or disables directory creation
 func (c Config) WithCreateDir(createDir bool) Config {
 	c.FileOptions.CreateDir = createDir
@@ -154,6 +597,26 @@ func (c Config) WithTimeRotationFormat(format string) Config {
 	return c
 }
 
+// WithRotationTime rotates daily, weekly and monthly files at the given
+// "HH:MM" wall-clock time instead of midnight
+func (c Config) WithRotationTime(clock string) Config {
+	c.FileOptions.RotationTime = clock
+	return c
+}
+
+// WithWeekStartDay sets the first day of the weeks of weekly rotation
+func (c Config) WithWeekStartDay(day time.Weekday) Config {
+	c.FileOptions.WeekStartDay = strings.ToLower(day.String())
+	return c
+}
+
+// WithSymlink enables or disables the link from Filename to the current
+// time-rotated file
+func (c Config) WithSymlink(symlink bool) Config {
+	c.FileOptions.Symlink = symlink
+	return c
+}
+
 // WithBothRotation enables both size and time-based rotation
 func (c Config) WithBothRotation(maxSize, maxAge, maxBackups int, interval TimeRotationInterval) Config {
 	c.FileOptions.RotationMode = RotationModeBoth
@@ -0,0 +1,82 @@
+// Package consulconfig applies logger settings stored in a Consul KV key and
+// follows its changes with blocking queries, so that the level and sampling
+// of a whole fleet can be changed within seconds without redeploying. The
+// key holds a JSON document:
+//
+//	{"level": "debug", "levels": {"db": "warn"}, "sampling": {"enabled": true, "initial": 100, "thereafter": 10, "tick": "1s"}}
+//
+// Omitted settings are left unchanged. Usage:
+//
+//	client, err := api.NewClient(api.DefaultConfig())
+//	if err != nil {
+//		return err
+//	}
+//	go consulconfig.Watch(ctx, client.KV(), "config/logger/billing", nil)
+package consulconfig
+
+import (
+	"context"
+	"time"
+
+	"github.com/csmart-libs/go-logger"
+	"github.com/csmart-libs/go-logger/internal/remoteconfig"
+	"github.com/hashicorp/consul/api"
+)
+
+// Client is the part of the Consul KV API used by Watch, implemented by
+// *api.KV
+type Client interface {
+	Get(key string, q *api.QueryOptions) (*api.KVPair, *api.QueryMeta, error)
+}
+
+const (
+	// waitTime is the longest a blocking query waits for a change
+	waitTime = 5 * time.Minute
+
+	// retryInterval is the wait before querying again after an error
+	retryInterval = 5 * time.Second
+)
+
+// Watch applies the settings stored in key to l, or to the global logger if
+// l is nil, then applies every change until ctx is done. Invalid documents
+// are logged and skipped, and failed queries are retried. It returns the
+// error of ctx.
+func Watch(ctx context.Context, client Client, key string, l logger.Logger) error {
+	applier, err := remoteconfig.New(l)
+	if err != nil {
+		return err
+	}
+
+	var index uint64
+	for {
+		query := (&api.QueryOptions{WaitIndex: index, WaitTime: waitTime}).WithContext(ctx)
+		pair, meta, err := client.Get(key, query)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			applier.Warn("consul logger settings query failed", err)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(retryInterval):
+			}
+			continue
+		}
+
+		switch {
+		case meta.LastIndex < index:
+			// The index went backwards, e.g. after a snapshot restore
+			index = 0
+			continue
+		case meta.LastIndex == index && index > 0:
+			// The wait timed out without change
+			continue
+		}
+		// An index of 0 would not block
+		index = max(meta.LastIndex, 1)
+		if pair != nil {
+			applier.Update("consul:"+key, pair.Value)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// loggerKey is the context key of the logger added by NewContext
+type loggerKey struct{}
+
+// NewContext returns a copy of ctx carrying l, so that request-scoped child
+// loggers can be passed down without adding a Logger parameter to every
+// function
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the logger added to ctx by NewContext, or the global
+// logger if there is none
+func FromContext(ctx context.Context) Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerKey{}).(Logger); ok {
+			return l
+		}
+	}
+	return GetLogger()
+}
+
+// fieldsKey is the context key of the fields added by ContextWithFields
+type fieldsKey struct{}
+
+// ContextWithFields returns a copy of ctx carrying fields in addition to
+// those already added to ctx. The context-aware log methods add them to
+// every entry, so a middleware can attach the route or user once for all
+// the code handling the request.
+func ContextWithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	existing := FieldsFromContext(ctx)
+	merged := make([]zap.Field, 0, len(existing)+len(fields))
+	merged = append(merged, existing...)
+	merged = append(merged, fields...)
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// FieldsFromContext returns the fields added to ctx by ContextWithFields,
+// oldest first
+func FieldsFromContext(ctx context.Context) []zap.Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).([]zap.Field)
+	return fields
+}
+
+// contextFields returns the fields carried by ctx: those added by
+// ContextWithFields, then the IDs of its active trace span
+func contextFields(ctx context.Context) []zap.Field {
+	if ctx == nil {
+		return nil
+	}
+	fields := FieldsFromContext(ctx)
+	if trace := traceFields(ctx); len(trace) > 0 {
+		fields = append(fields[:len(fields):len(fields)], trace...)
+	}
+	return fields
+}
+
+// withContextFields appends the fields carried by ctx to fields
+func withContextFields(ctx context.Context, fields []zap.Field) []zap.Field {
+	extra := contextFields(ctx)
+	if len(extra) == 0 {
+		return fields
+	}
+	return append(fields[:len(fields):len(fields)], extra...)
+}
+
+// ContextLogger is an optional extension of Logger, implemented by
+// *ZapLogger, whose methods add the fields carried by a context. The
+// package functions such as InfoContext and Recover use it when the logger
+// implements it, and otherwise add the context fields to the plain methods.
+type ContextLogger interface {
+	DebugContext(ctx context.Context, msg string, fields ...zap.Field)
+	InfoContext(ctx context.Context, msg string, fields ...zap.Field)
+	WarnContext(ctx context.Context, msg string, fields ...zap.Field)
+	ErrorContext(ctx context.Context, msg string, fields ...zap.Field)
+}
+
+// DebugContext logs a debug message with the fields carried by ctx
+func (l *ZapLogger) DebugContext(ctx context.Context, msg string, fields ...zap.Field) {
+	if ce := l.logger.Check(zapcore.DebugLevel, msg); ce != nil {
+		ce.Write(withContextFields(ctx, fields)...)
+	}
+}
+
+// InfoContext logs an info message with the fields carried by ctx
+func (l *ZapLogger) InfoContext(ctx context.Context, msg string, fields ...zap.Field) {
+	if ce := l.logger.Check(zapcore.InfoLevel, msg); ce != nil {
+		ce.Write(withContextFields(ctx, fields)...)
+	}
+}
+
+// WarnContext logs a warning message with the fields carried by ctx
+func (l *ZapLogger) WarnContext(ctx context.Context, msg string, fields ...zap.Field) {
+	if ce := l.logger.Check(zapcore.WarnLevel, msg); ce != nil {
+		ce.Write(withContextFields(ctx, fields)...)
+	}
+}
+
+// ErrorContext logs an error message with the fields carried by ctx
+func (l *ZapLogger) ErrorContext(ctx context.Context, msg string, fields ...zap.Field) {
+	if ce := l.logger.Check(zapcore.ErrorLevel, msg); ce != nil {
+		ce.Write(withContextFields(ctx, fields)...)
+	}
+}
@@ -0,0 +1,157 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultDedupInterval is how long repeats of an entry are collapsed before
+// the repeat count is flushed
+const DefaultDedupInterval = 5 * time.Second
+
+// dedupState tracks the last entry written through a dedup core and its
+// tree of children
+type dedupState struct {
+	mu       sync.Mutex
+	interval time.Duration
+	timer    *time.Timer
+	report   func(error)
+
+	// contexts numbers the children created through With
+	contexts atomic.Uint64
+
+	key    string
+	core   zapcore.Core
+	entry  zapcore.Entry
+	fields []zapcore.Field
+	count  int
+	since  time.Time
+}
+
+// flush writes the pending repeats as one entry with a repeat_count field.
+// The caller holds mu.
+func (s *dedupState) flush() error {
+	if s.count == 0 {
+		return nil
+	}
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	count := s.count
+	s.count = 0
+	checked := s.core.Check(s.entry, nil)
+	if checked == nil {
+		return nil
+	}
+	fields := append(s.fields[:len(s.fields):len(s.fields)],
+		zapcore.Field{Key: "repeat_count", Type: zapcore.Int64Type, Integer: int64(count)})
+	return writeChecked(checked, s.entry, fields)
+}
+
+// flushLocked flushes the pending repeats outside of a write, reporting
+// write errors to the internal error handler
+func (s *dedupState) flushLocked() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.flush(); err != nil {
+		s.report(err)
+	}
+}
+
+// dedupCore collapses consecutive identical entries: entries of one logger
+// with the same level, caller and message, whatever their fields. The first
+// entry is written as is; its repeats are counted and written as a single
+// entry with a repeat_count field when a different entry arrives, the
+// interval elapses or the logger is synced.
+type dedupCore struct {
+	zapcore.Core
+	state *dedupState
+
+	// context identifies the child created through With, so that entries
+	// of children with different fields are not collapsed
+	context uint64
+}
+
+func newDedupCore(core zapcore.Core, options DedupOptions, report func(error)) zapcore.Core {
+	interval := options.Interval
+	if interval <= 0 {
+		interval = DefaultDedupInterval
+	}
+	return &dedupCore{Core: core, state: &dedupState{interval: interval, report: report}}
+}
+
+func (c *dedupCore) With(fields []zapcore.Field) zapcore.Core {
+	return &dedupCore{
+		Core:    c.Core.With(fields),
+		state:   c.state,
+		context: c.state.contexts.Add(1),
+	}
+}
+
+func (c *dedupCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	inner := c.Core.Check(ent, nil)
+	if inner == nil {
+		return ce
+	}
+	return ce.AddCore(inner.Entry, &dedupWriter{core: c, checked: inner})
+}
+
+func (c *dedupCore) Sync() error {
+	c.state.mu.Lock()
+	err := c.state.flush()
+	c.state.mu.Unlock()
+	return errors.Join(err, c.Core.Sync())
+}
+
+// dedupWriter is a single-use core that writes an entry through the
+// CheckedEntry of the wrapped core unless it repeats the previous one
+type dedupWriter struct {
+	core    *dedupCore
+	checked *zapcore.CheckedEntry
+}
+
+func (w *dedupWriter) Enabled(zapcore.Level) bool { return true }
+
+func (w *dedupWriter) With([]zapcore.Field) zapcore.Core { return w }
+
+func (w *dedupWriter) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, w)
+}
+
+func (w *dedupWriter) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	key := fmt.Sprintf("%d\x00%d\x00%s\x00%s\x00%s",
+		w.core.context, ent.Level, ent.LoggerName, ent.Caller.String(), ent.Message)
+
+	s := w.core.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key == s.key {
+		if s.count == 0 {
+			s.since = ent.Time
+			s.timer = time.AfterFunc(s.interval, s.flushLocked)
+		}
+		s.count++
+		s.entry = ent
+		if ent.Time.Sub(s.since) >= s.interval {
+			return s.flush()
+		}
+		return nil
+	}
+
+	err := s.flush()
+	s.key = key
+	s.core = w.core.Core
+	s.entry = ent
+	s.fields = append([]zapcore.Field(nil), fields...)
+
+	return errors.Join(err, writeChecked(w.checked, ent, fields))
+}
+
+func (w *dedupWriter) Sync() error { return nil }
@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"syscall"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Disk full policy constants
+const (
+	DiskFullDrop   = "drop"   // drop entries and count them
+	DiskFullStdout = "stdout" // write entries to stdout
+	DiskFullPurge  = "purge"  // remove the oldest rotated backups, then drop
+)
+
+// diskFullWriter applies the disk full policy when a file write fails with
+// ENOSPC. Other errors are returned to the caller. The chosen action and
+// the recovery are reported to the internal error hook.
+type diskFullWriter struct {
+	primary zapcore.WriteSyncer
+	policy  string
+	name    string
+	backups func() []string
+	report  func(error)
+	metrics MetricsRecorder
+	full    atomic.Bool
+	dropped atomic.Int64
+}
+
+// newDiskFullWriter wraps the writer of a file sink per its disk full
+// policy. file is the writer of the file, used to find rotated backups.
+func newDiskFullWriter(name string, primary zapcore.WriteSyncer, file io.Writer, options FileOptions, config Config) zapcore.WriteSyncer {
+	policy := strings.ToLower(options.DiskFullPolicy)
+	if policy == "" {
+		return primary
+	}
+	active := activeFilename(file, options)
+	return &diskFullWriter{
+		primary: primary,
+		policy:  policy,
+		name:    name,
+		backups: func() []string { return rotatedBackups(options.Filename, active()) },
+		report:  config.internalErrorHandler(),
+		metrics: config.metrics(),
+	}
+}
+
+func (w *diskFullWriter) Write(p []byte) (int, error) {
+	n, err := w.primary.Write(p)
+	if err == nil {
+		if w.full.CompareAndSwap(true, false) {
+			w.report(fmt.Errorf("logger: %s sink has space again, %d entries dropped", w.name, w.dropped.Swap(0)))
+		}
+		return n, nil
+	}
+	if !errors.Is(err, syscall.ENOSPC) {
+		return n, err
+	}
+	first := w.full.CompareAndSwap(false, true)
+
+	switch w.policy {
+	case DiskFullStdout:
+		if first {
+			w.report(fmt.Errorf("logger: %s sink is out of space, writing to stdout: %w", w.name, err))
+		}
+		return os.Stdout.Write(p)
+	case DiskFullPurge:
+		removed := 0
+		for _, backup := range w.backups() {
+			if os.Remove(backup) != nil {
+				continue
+			}
+			removed++
+			if n, err = w.primary.Write(p); err == nil || !errors.Is(err, syscall.ENOSPC) {
+				w.full.Store(false)
+				w.report(fmt.Errorf("logger: %s sink is out of space, removed %d rotated backups", w.name, removed))
+				return n, err
+			}
+		}
+		if first || removed > 0 {
+			w.report(fmt.Errorf("logger: %s sink is out of space, removed %d rotated backups, dropping entries: %w", w.name, removed, err))
+		}
+	default:
+		if first {
+			w.report(fmt.Errorf("logger: %s sink is out of space, dropping entries: %w", w.name, err))
+		}
+	}
+	w.dropped.Add(1)
+	w.metrics.RecordDropped(DropReasonDiskFull, 1)
+	return len(p), nil
+}
+
+func (w *diskFullWriter) Sync() error {
+	return w.primary.Sync()
+}
+
+// rotatedBackups lists the rotated backups of a log file, oldest first.
+// Backups are named <name>-<timestamp><ext>, or after the {time} template of
+// the filename, optionally compressed; active is the file currently written
+// to and is never listed.
+func rotatedBackups(filename, active string) []string {
+	dir := filepath.Dir(filename)
+	ext := filepath.Ext(filename)
+	prefix := strings.TrimSuffix(filepath.Base(filename), ext) + "-"
+	if i := strings.Index(filepath.Base(filename), timePlaceholder); i >= 0 {
+		prefix = filepath.Base(filename)[:i]
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	type backup struct {
+		path    string
+		modTime int64
+	}
+	var backups []backup
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) || !strings.Contains(name[len(prefix):], ext) {
+			continue
+		}
+		// Timestamps start with a digit, unlike other files sharing the prefix
+		if rest := name[len(prefix):]; rest == "" || rest[0] < '0' || rest[0] > '9' {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if path == filepath.Clean(active) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: path, modTime: info.ModTime().UnixNano()})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].modTime < backups[j].modTime })
+
+	paths := make([]string, len(backups))
+	for i, b := range backups {
+		paths[i] = b.path
+	}
+	return paths
+}
@@ -0,0 +1,88 @@
+package logger
+
+import "go.uber.org/zap/zapcore"
+
+// compileDropFilter compiles a drop filter
+func compileDropFilter(f DropFilter) (entryMatcher, error) {
+	return newEntryMatcher(f.Message, f.Logger, f.Level, f.Field, f.FieldValue)
+}
+
+// dropCore drops the entries matching a drop filter before they reach the
+// later processing stages and the encoders. The filters are read from the
+// rule set on every entry, so they can change at runtime.
+type dropCore struct {
+	zapcore.Core
+	rules   *RuleSet
+	metrics MetricsRecorder
+
+	// fields are the fields added through With, matched along with the
+	// entry fields
+	fields []zapcore.Field
+}
+
+func newDropCore(core zapcore.Core, rules *RuleSet, metrics MetricsRecorder) zapcore.Core {
+	return &dropCore{Core: core, rules: rules, metrics: metrics}
+}
+
+func (c *dropCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	clone.fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	return &clone
+}
+
+func (c *dropCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	// Filters without field condition are decided here; the others once
+	// the fields are known
+	var candidates []entryMatcher
+	for _, f := range c.rules.load().drops {
+		if !f.matchEntry(ent) {
+			continue
+		}
+		if f.field == "" {
+			c.metrics.RecordDropped(DropReasonFiltered, 1)
+			return ce
+		}
+		candidates = append(candidates, f)
+	}
+	if len(candidates) == 0 {
+		return c.Core.Check(ent, ce)
+	}
+	inner := c.Core.Check(ent, nil)
+	if inner == nil {
+		return ce
+	}
+	return ce.AddCore(inner.Entry, &dropWriter{core: c, checked: inner, candidates: candidates})
+}
+
+// dropWriter is a single-use core that writes an entry through the
+// CheckedEntry of the wrapped core unless its fields match a filter
+type dropWriter struct {
+	core       *dropCore
+	checked    *zapcore.CheckedEntry
+	candidates []entryMatcher
+}
+
+func (w *dropWriter) Enabled(zapcore.Level) bool { return true }
+
+func (w *dropWriter) With([]zapcore.Field) zapcore.Core { return w }
+
+func (w *dropWriter) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, w)
+}
+
+func (w *dropWriter) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	all := fields
+	if len(w.core.fields) > 0 {
+		all = append(w.core.fields[:len(w.core.fields):len(w.core.fields)], fields...)
+	}
+	for _, f := range w.candidates {
+		if f.matchFields(all) {
+			w.core.metrics.RecordDropped(DropReasonFiltered, 1)
+			return nil
+		}
+	}
+	return writeChecked(w.checked, ent, fields)
+}
+
+func (w *dropWriter) Sync() error { return nil }
@@ -0,0 +1,116 @@
+// Package echomw provides Echo middleware logging requests and recovered
+// panics through the logger package, with the same fields as ginmw.
+//
+//	e := echo.New()
+//	e.Use(echomw.Logger(), echomw.Recovery())
+package echomw
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/csmart-libs/go-logger"
+	"github.com/csmart-libs/go-logger/accesslog"
+	"github.com/csmart-libs/go-logger/internal/httplog"
+	"github.com/labstack/echo/v4"
+)
+
+// Logger returns middleware logging each request with its method, path,
+// status, latency, client IP and request ID through the global logger
+func Logger() echo.MiddlewareFunc {
+	return LoggerWith(nil)
+}
+
+// LoggerWith is like Logger but logs through l. A nil l uses the global
+// logger.
+func LoggerWith(l logger.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				// Let the error handler write the response so that its
+				// status is logged
+				c.Error(err)
+			}
+			httplog.Log(httplog.LoggerOrGlobal(l), request(c, start))
+			return nil
+		}
+	}
+}
+
+// AccessLog returns middleware writing an access log line for each request
+// to access, e.g. in the Apache combined format for legacy analyzers. It
+// runs alongside Logger.
+func AccessLog(access *accesslog.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+			_ = access.Log(request(c, start).AccessEntry())
+			return nil
+		}
+	}
+}
+
+// Recovery returns middleware recovering from panics in later handlers,
+// logging them with their stack trace through the global logger and passing
+// them to the error handler, which responds with status 500
+func Recovery() echo.MiddlewareFunc {
+	return RecoveryWith(nil)
+}
+
+// RecoveryWith is like Recovery but logs through l. A nil l uses the global
+// logger.
+func RecoveryWith(l logger.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			start := time.Now()
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+				r := request(c, start)
+				r.Status = http.StatusInternalServerError
+				httplog.LogPanic(httplog.LoggerOrGlobal(l), r, recovered)
+				recoveredErr, ok := recovered.(error)
+				if !ok {
+					recoveredErr = fmt.Errorf("%v", recovered)
+				}
+				err = echo.NewHTTPError(http.StatusInternalServerError).SetInternal(recoveredErr)
+			}()
+			return next(c)
+		}
+	}
+}
+
+func request(c echo.Context, start time.Time) httplog.Request {
+	requestID := c.Request().Header.Get(httplog.RequestIDHeader)
+	if requestID == "" {
+		requestID = c.Response().Header().Get(httplog.RequestIDHeader)
+	}
+	req := c.Request()
+	return httplog.Request{
+		Start:         start,
+		Method:        req.Method,
+		Path:          req.URL.Path,
+		URI:           req.RequestURI,
+		Proto:         req.Proto,
+		Status:        c.Response().Status,
+		Bytes:         c.Response().Size,
+		Latency:       time.Since(start),
+		ClientIP:      c.RealIP(),
+		RequestID:     requestID,
+		Referer:       req.Referer(),
+		UserAgent:     req.UserAgent(),
+		Authorization: req.Header.Get("Authorization"),
+	}
+}
@@ -0,0 +1,186 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// encoderSet creates the encoders of the sinks. Sinks with the same
+// encoding share one encoder.
+type encoderSet struct {
+	config   Config
+	encoders map[string]zapcore.Encoder
+}
+
+func newEncoderSet(config Config) *encoderSet {
+	return &encoderSet{config: config, encoders: make(map[string]zapcore.Encoder)}
+}
+
+// get returns the encoder for an encoding; empty means Config.Encoding
+func (s *encoderSet) get(encoding string) (zapcore.Encoder, error) {
+	if encoding == "" {
+		encoding = s.config.Encoding
+	}
+	if encoder, ok := s.encoders[encoding]; ok {
+		return encoder, nil
+	}
+	config := s.config
+	config.Encoding = encoding
+	encoder, err := buildEncoder(config)
+	if err != nil {
+		return nil, err
+	}
+	s.encoders[encoding] = encoder
+	return encoder, nil
+}
+
+// buildEncoder creates the encoder described by the configuration
+func buildEncoder(config Config) (zapcore.Encoder, error) {
+	// Create encoder config based on environment
+	var encoderConfig zapcore.EncoderConfig
+	if config.Environment == "production" {
+		encoderConfig = zap.NewProductionEncoderConfig()
+	} else {
+		encoderConfig = zap.NewDevelopmentEncoderConfig()
+	}
+
+	// Configure time encoding
+	timeEncoder, err := newTimeEncoder(config)
+	if err != nil {
+		return nil, err
+	}
+	encoderConfig.TimeKey = "timestamp"
+	encoderConfig.EncodeTime = timeEncoder
+
+	// Configure caller encoding
+	configureCaller(config, &encoderConfig)
+
+	// Configure console colors
+	configureColors(config, &encoderConfig)
+	encoderConfig.EncodeLevel = customLevelEncoder(encoderConfig.EncodeLevel)
+
+	return newEncoder(config, encoderConfig), nil
+}
+
+// newEncoder creates the encoder for the configured encoding
+func newEncoder(config Config, encoderConfig zapcore.EncoderConfig) zapcore.Encoder {
+	switch config.Encoding {
+	case EncodingJSON:
+		return zapcore.NewJSONEncoder(encoderConfig)
+	case EncodingJSONPretty:
+		return newPrettyJSONEncoder(zapcore.NewJSONEncoder(encoderConfig), config.PrettyJSON)
+	default:
+		return zapcore.NewConsoleEncoder(encoderConfig)
+	}
+}
+
+// newTimeEncoder returns the time encoder for the configured time encoding and timezone
+func newTimeEncoder(config Config) (zapcore.TimeEncoder, error) {
+	var base zapcore.TimeEncoder
+	switch config.TimeEncoding {
+	case TimeEncodingRFC3339:
+		base = zapcore.RFC3339TimeEncoder
+	case TimeEncodingRFC3339Nano:
+		base = zapcore.RFC3339NanoTimeEncoder
+	case TimeEncodingEpoch:
+		return zapcore.EpochTimeEncoder, nil
+	case TimeEncodingEpochMillis:
+		return zapcore.EpochMillisTimeEncoder, nil
+	case TimeEncodingEpochNanos:
+		return zapcore.EpochNanosTimeEncoder, nil
+	default:
+		base = zapcore.ISO8601TimeEncoder
+	}
+
+	if config.Timezone == "" {
+		return base, nil
+	}
+
+	loc, err := time.LoadLocation(config.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("logger: invalid timezone %q: %w", config.Timezone, err)
+	}
+
+	return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		base(t.In(loc), enc)
+	}, nil
+}
+
+// configureCaller applies the configured caller format to the encoder config
+func configureCaller(config Config, encoderConfig *zapcore.EncoderConfig) {
+	if !config.callerEnabled() {
+		encoderConfig.CallerKey = zapcore.OmitKey
+		return
+	}
+
+	if config.CallerFormat == CallerFormatFull {
+		encoderConfig.EncodeCaller = zapcore.FullCallerEncoder
+	} else {
+		encoderConfig.EncodeCaller = zapcore.ShortCallerEncoder
+	}
+
+	if config.CallerFunction {
+		encoderConfig.FunctionKey = "func"
+	}
+}
+
+var prettyBufferPool = buffer.NewPool()
+
+// prettyJSONEncoder re-indents the output of a JSON encoder
+type prettyJSONEncoder struct {
+	zapcore.Encoder
+	indent   string
+	sortKeys bool
+}
+
+func newPrettyJSONEncoder(enc zapcore.Encoder, options PrettyJSONOptions) zapcore.Encoder {
+	indent := options.Indent
+	if indent == "" {
+		indent = "  "
+	}
+	return &prettyJSONEncoder{Encoder: enc, indent: indent, sortKeys: options.SortKeys}
+}
+
+func (e *prettyJSONEncoder) Clone() zapcore.Encoder {
+	return &prettyJSONEncoder{Encoder: e.Encoder.Clone(), indent: e.indent, sortKeys: e.sortKeys}
+}
+
+func (e *prettyJSONEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	buf, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	defer buf.Free()
+
+	raw := bytes.TrimSpace(buf.Bytes())
+	var indented bytes.Buffer
+	if e.sortKeys {
+		// Decoding into a map lets encoding/json emit the keys in sorted order
+		var entry map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return nil, err
+		}
+		// zap does not escape HTML characters, so neither does the re-encoding
+		var sorted bytes.Buffer
+		enc := json.NewEncoder(&sorted)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(entry); err != nil {
+			return nil, err
+		}
+		raw = bytes.TrimSpace(sorted.Bytes())
+	}
+	if err := json.Indent(&indented, raw, "", e.indent); err != nil {
+		return nil, err
+	}
+
+	out := prettyBufferPool.Get()
+	out.Write(indented.Bytes())
+	out.AppendString(zapcore.DefaultLineEnding)
+	return out, nil
+}
@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// encryptionSuffix is the suffix of encrypted rotated files
+const encryptionSuffix = ".age"
+
+// parseRecipients parses age X25519 public keys
+func parseRecipients(keys []string) ([]age.Recipient, error) {
+	var recipients []age.Recipient
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		recipient, err := age.ParseX25519Recipient(key)
+		if err != nil {
+			return nil, fmt.Errorf("logger: invalid encryption recipient %q: %w", key, err)
+		}
+		recipients = append(recipients, recipient)
+	}
+	return recipients, nil
+}
+
+// isEncrypted reports whether a rotated file is already encrypted
+func isEncrypted(path string) bool {
+	return strings.HasSuffix(path, encryptionSuffix)
+}
+
+// encryptFile encrypts a rotated file next to it and removes the original,
+// returning the path of the encrypted file
+func encryptFile(path string, recipients []age.Recipient) (string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return "", err
+	}
+	dstPath := path + encryptionSuffix
+	dst, err := os.OpenFile(dstPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode())
+	if err != nil {
+		return "", err
+	}
+
+	if err := encryptTo(dst, src, recipients); err != nil {
+		dst.Close()
+		os.Remove(dstPath)
+		return "", err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(dstPath)
+		return "", err
+	}
+	// Keep the modification time, which retention goes by
+	os.Chtimes(dstPath, info.ModTime(), info.ModTime())
+	src.Close()
+	if err := os.Remove(path); err != nil {
+		return "", err
+	}
+	return dstPath, nil
+}
+
+// encryptTo writes the encrypted content of src to dst and syncs it
+func encryptTo(dst *os.File, src io.Reader, recipients []age.Recipient) error {
+	w, err := age.Encrypt(dst, recipients...)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, src); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return dst.Sync()
+}
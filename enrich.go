@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"strconv"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// enrichmentFields returns the static host and process fields enabled in config
+func enrichmentFields(options EnrichmentOptions) []zap.Field {
+	var fields []zap.Field
+	if options.Hostname {
+		if hostname, err := os.Hostname(); err == nil {
+			fields = append(fields, zap.String("hostname", hostname))
+		}
+	}
+	if options.PID {
+		fields = append(fields, zap.Int("pid", os.Getpid()))
+	}
+	return fields
+}
+
+// envFields resolves environment variables into fields ordered by key
+func envFields(mapping map[string]string) []zap.Field {
+	values := make(map[string]any, len(mapping))
+	for envVar, key := range mapping {
+		if value, ok := os.LookupEnv(envVar); ok {
+			values[key] = value
+		}
+	}
+	return Fields(values)
+}
+
+// newGoroutineCore adds the ID of the logging goroutine to every entry
+func newGoroutineCore(core zapcore.Core) zapcore.Core {
+	return newTransformCore(core, &entryTransform{
+		write: func(_ *zapcore.Entry, fields []zapcore.Field) ([]zapcore.Field, bool) {
+			return append(fields, zap.Uint64("goroutine", goroutineID())), true
+		},
+	})
+}
+
+var goroutinePrefix = []byte("goroutine ")
+
+// goroutineID parses the current goroutine ID from the runtime stack header
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, goroutinePrefix)
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
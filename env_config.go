@@ -1,9 +1,11 @@
 package logger
 
 import (
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // ConfigFromEnv creates logger configuration from environment variables
@@ -30,33 +32,263 @@ func ConfigFromEnv() Config {
 		config.Level = strings.ToLower(level)
 	}
 
+	// Get per-module levels, e.g. "http=debug,db=warn,*=info"
+	if levels := os.Getenv("LOG_LEVELS"); levels != "" {
+		root, named := parseLevelSpec(levels)
+		if root != "" {
+			config.Level = root
+		}
+		if len(named) > 0 {
+			config.Levels = named
+		}
+	}
+
 	// Get encoding
 	if encoding := os.Getenv("LOG_ENCODING"); encoding != "" {
 		config.Encoding = strings.ToLower(encoding)
 	}
 
+	// Get console color mode
+	if colorMode := os.Getenv("LOG_COLOR"); colorMode != "" {
+		config.Colors.Mode = strings.ToLower(colorMode)
+	}
+
+	// Get timestamp time zone
+	if tz := os.Getenv("LOG_TIMEZONE"); tz != "" {
+		config.Timezone = tz
+	}
+
+	// Get timestamp encoding
+	if timeEncoding := os.Getenv("LOG_TIME_ENCODING"); timeEncoding != "" {
+		config.TimeEncoding = strings.ToLower(timeEncoding)
+	}
+
+	// Get caller options
+	if callerFormat := os.Getenv("LOG_CALLER_FORMAT"); callerFormat != "" {
+		config.CallerFormat = strings.ToLower(callerFormat)
+	}
+	if callerFunction := os.Getenv("LOG_CALLER_FUNCTION"); callerFunction != "" {
+		config.CallerFunction = strings.ToLower(callerFunction) == "true"
+	}
+
+	if disableCaller := os.Getenv("LOG_DISABLE_CALLER"); disableCaller != "" {
+		config.DisableCaller = strings.ToLower(disableCaller) == "true"
+	}
+	if disableStacktrace := os.Getenv("LOG_DISABLE_STACKTRACE"); disableStacktrace != "" {
+		config.DisableStacktrace = strings.ToLower(disableStacktrace) == "true"
+	}
+
+	// Get constant fields from LOG_FIELD_<name>=<value>
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		if key, ok := strings.CutPrefix(name, "LOG_FIELD_"); ok && key != "" {
+			config = config.WithInitialField(strings.ToLower(key), value)
+		}
+	}
+
+	// Get enrichment options
+	if hostname := os.Getenv("LOG_ENRICH_HOSTNAME"); hostname != "" {
+		config.Enrichment.Hostname = strings.ToLower(hostname) == "true"
+	}
+	if pid := os.Getenv("LOG_ENRICH_PID"); pid != "" {
+		config.Enrichment.PID = strings.ToLower(pid) == "true"
+	}
+	if goroutineID := os.Getenv("LOG_ENRICH_GOROUTINE_ID"); goroutineID != "" {
+		config.Enrichment.GoroutineID = strings.ToLower(goroutineID) == "true"
+	}
+
+	// Get sampling options
+	if initial := os.Getenv("LOG_SAMPLING_INITIAL"); initial != "" {
+		if n, err := strconv.Atoi(initial); err == nil {
+			config.Sampling.Enabled = true
+			config.Sampling.Initial = n
+		} else {
+			config.invalidEnv("LOG_SAMPLING_INITIAL", initial, err)
+		}
+	}
+	if thereafter := os.Getenv("LOG_SAMPLING_THEREAFTER"); thereafter != "" {
+		if n, err := strconv.Atoi(thereafter); err == nil {
+			config.Sampling.Thereafter = n
+		} else {
+			config.invalidEnv("LOG_SAMPLING_THEREAFTER", thereafter, err)
+		}
+	}
+	if unsampled := os.Getenv("LOG_SAMPLING_UNSAMPLED_LEVEL"); unsampled != "" {
+		config.Sampling.UnsampledLevel = strings.ToLower(unsampled)
+	}
+	if tick := os.Getenv("LOG_SAMPLING_TICK"); tick != "" {
+		if d, err := time.ParseDuration(tick); err == nil {
+			config.Sampling.Tick = d
+		} else {
+			config.invalidEnv("LOG_SAMPLING_TICK", tick, err)
+		}
+	}
+	if rate := os.Getenv("LOG_RATE_LIMIT"); rate != "" {
+		if r, err := strconv.ParseFloat(rate, 64); err == nil {
+			config.RateLimit.Enabled = true
+			config.RateLimit.Rate = r
+		} else {
+			config.invalidEnv("LOG_RATE_LIMIT", rate, err)
+		}
+	}
+	if burst := os.Getenv("LOG_RATE_LIMIT_BURST"); burst != "" {
+		if n, err := strconv.Atoi(burst); err == nil {
+			config.RateLimit.Burst = n
+		} else {
+			config.invalidEnv("LOG_RATE_LIMIT_BURST", burst, err)
+		}
+	}
+	if key := os.Getenv("LOG_RATE_LIMIT_KEY"); key != "" {
+		config.RateLimit.KeyField = key
+	}
+	if level := os.Getenv("LOG_RATE_LIMIT_UNLIMITED_LEVEL"); level != "" {
+		config.RateLimit.UnlimitedLevel = strings.ToLower(level)
+	}
+	if dedup := os.Getenv("LOG_DEDUP"); dedup != "" {
+		config.Dedup.Enabled = strings.ToLower(dedup) == "true"
+	}
+	if interval := os.Getenv("LOG_DEDUP_INTERVAL"); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil {
+			config.Dedup.Enabled = true
+			config.Dedup.Interval = d
+		} else {
+			config.invalidEnv("LOG_DEDUP_INTERVAL", interval, err)
+		}
+	}
+	if maxEntries := os.Getenv("LOG_BREAKER_MAX_ENTRIES"); maxEntries != "" {
+		if n, err := strconv.Atoi(maxEntries); err == nil {
+			config.CircuitBreaker.Enabled = true
+			config.CircuitBreaker.MaxEntriesPerSecond = n
+		} else {
+			config.invalidEnv("LOG_BREAKER_MAX_ENTRIES", maxEntries, err)
+		}
+	}
+	if maxBytes := os.Getenv("LOG_BREAKER_MAX_BYTES"); maxBytes != "" {
+		if n, err := strconv.ParseInt(maxBytes, 10, 64); err == nil {
+			config.CircuitBreaker.Enabled = true
+			config.CircuitBreaker.MaxBytesPerSecond = n
+		} else {
+			config.invalidEnv("LOG_BREAKER_MAX_BYTES", maxBytes, err)
+		}
+	}
+	if expvarName := os.Getenv("LOG_EXPVAR"); expvarName != "" {
+		config.Expvar = expvarName
+	}
+	if fallback := os.Getenv("LOG_FALLBACK_OUTPUT"); fallback != "" {
+		config.FallbackOutput = strings.ToLower(fallback)
+	}
+	if async := os.Getenv("LOG_ASYNC"); async != "" {
+		config.Async.Enabled = strings.ToLower(async) == "true"
+	}
+	if queueSize := os.Getenv("LOG_ASYNC_QUEUE_SIZE"); queueSize != "" {
+		if n, err := strconv.Atoi(queueSize); err == nil {
+			config.Async.QueueSize = n
+		} else {
+			config.invalidEnv("LOG_ASYNC_QUEUE_SIZE", queueSize, err)
+		}
+	}
+	if nonBlocking := os.Getenv("LOG_ASYNC_NON_BLOCKING"); nonBlocking != "" {
+		if strings.ToLower(nonBlocking) == "true" {
+			config.Async.Enabled = true
+			config.Async.NonBlocking = true
+		}
+	}
+	if maxWait := os.Getenv("LOG_ASYNC_MAX_WAIT"); maxWait != "" {
+		if d, err := time.ParseDuration(maxWait); err == nil {
+			config.Async.MaxWait = d
+		} else {
+			config.invalidEnv("LOG_ASYNC_MAX_WAIT", maxWait, err)
+		}
+	}
+	if size := os.Getenv("LOG_BACKTRACE_SIZE"); size != "" {
+		if n, err := strconv.Atoi(size); err == nil {
+			config.Backtrace.Enabled = true
+			config.Backtrace.Size = n
+		} else {
+			config.invalidEnv("LOG_BACKTRACE_SIZE", size, err)
+		}
+	}
+	if level := os.Getenv("LOG_BACKTRACE_LEVEL"); level != "" {
+		config.Backtrace.Level = strings.ToLower(level)
+	}
+	if level := os.Getenv("LOG_BREAKER_LEVEL"); level != "" {
+		config.CircuitBreaker.Level = strings.ToLower(level)
+	}
+	if cooldown := os.Getenv("LOG_BREAKER_COOLDOWN"); cooldown != "" {
+		if d, err := time.ParseDuration(cooldown); err == nil {
+			config.CircuitBreaker.Cooldown = d
+		} else {
+			config.invalidEnv("LOG_BREAKER_COOLDOWN", cooldown, err)
+		}
+	}
+
+	// Get redaction options
+	if redactKeys := os.Getenv("LOG_REDACT_KEYS"); redactKeys != "" {
+		config = config.WithRedaction(strings.Split(redactKeys, ",")...)
+	}
+
+	if presets := os.Getenv("LOG_SCRUB_PRESETS"); presets != "" {
+		config = config.WithScrubbingPresets(strings.Split(strings.ToLower(presets), ",")...)
+	}
+
 	// Get output paths
 	if outputs := os.Getenv("LOG_OUTPUT_PATHS"); outputs != "" {
-		config.OutputPaths = strings.Split(outputs, ",")
+		config.OutputPaths = nil
+		for _, path := range strings.Split(outputs, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				config.OutputPaths = append(config.OutputPaths, path)
+			}
+		}
 	}
 
 	// Get file options from environment
 	if filename := os.Getenv("LOG_FILE"); filename != "" {
 		config.FileOptions.Filename = filename
 	}
+	if filename := os.Getenv("LOG_AUDIT_FILE"); filename != "" {
+		config.Audit.File.Filename = filename
+	}
+	if toAudit := os.Getenv("LOG_SECURITY_TO_AUDIT"); toAudit != "" {
+		config.Security.RouteToAudit = strings.ToLower(toAudit) == "true"
+	}
+	if field := os.Getenv("LOG_TENANT_FIELD"); field != "" {
+		config.Tenants.Field = field
+	}
+	if filename := os.Getenv("LOG_TENANT_FILE"); filename != "" {
+		config.Tenants.File.Filename = filename
+	}
+	if repanic := os.Getenv("LOG_REPANIC_ON_RECOVER"); repanic != "" {
+		config.RepanicOnRecover = strings.ToLower(repanic) == "true"
+	}
+	if strict := os.Getenv("LOG_STRICT_VALIDATION"); strict != "" {
+		config.StrictValidation = strings.ToLower(strict) == "true"
+	}
 	if maxSize := os.Getenv("LOG_FILE_MAX_SIZE"); maxSize != "" {
 		if size, err := strconv.Atoi(maxSize); err == nil {
 			config.FileOptions.MaxSize = size
+		} else {
+			config.invalidEnv("LOG_FILE_MAX_SIZE", maxSize, err)
 		}
 	}
 	if maxAge := os.Getenv("LOG_FILE_MAX_AGE"); maxAge != "" {
 		if age, err := strconv.Atoi(maxAge); err == nil {
 			config.FileOptions.MaxAge = age
+		} else {
+			config.invalidEnv("LOG_FILE_MAX_AGE", maxAge, err)
 		}
 	}
 	if maxBackups := os.Getenv("LOG_FILE_MAX_BACKUPS"); maxBackups != "" {
 		if backups, err := strconv.Atoi(maxBackups); err == nil {
 			config.FileOptions.MaxBackups = backups
+		} else {
+			config.invalidEnv("LOG_FILE_MAX_BACKUPS", maxBackups, err)
+		}
+	}
+	if maxTotalSize := os.Getenv("LOG_FILE_MAX_TOTAL_SIZE"); maxTotalSize != "" {
+		if size, err := strconv.Atoi(maxTotalSize); err == nil {
+			config.FileOptions.MaxTotalSize = size
+		} else {
+			config.invalidEnv("LOG_FILE_MAX_TOTAL_SIZE", maxTotalSize, err)
 		}
 	}
 	if localTime := os.Getenv("LOG_FILE_LOCAL_TIME"); localTime != "" {
@@ -65,6 +297,36 @@ func ConfigFromEnv() Config {
 	if compress := os.Getenv("LOG_FILE_COMPRESS"); compress != "" {
 		config.FileOptions.Compress = strings.ToLower(compress) == "true"
 	}
+	if compression := os.Getenv("LOG_FILE_COMPRESSION"); compression != "" {
+		config.FileOptions.Compression = strings.ToLower(compression)
+	}
+	if level := os.Getenv("LOG_FILE_COMPRESSION_LEVEL"); level != "" {
+		if l, err := strconv.Atoi(level); err == nil {
+			config.FileOptions.CompressionLevel = l
+		} else {
+			config.invalidEnv("LOG_FILE_COMPRESSION_LEVEL", level, err)
+		}
+	}
+	if delay := os.Getenv("LOG_FILE_COMPRESS_DELAY"); delay != "" {
+		if d, err := strconv.Atoi(delay); err == nil {
+			config.FileOptions.CompressDelay = d
+		} else {
+			config.invalidEnv("LOG_FILE_COMPRESS_DELAY", delay, err)
+		}
+	}
+	if recipients := os.Getenv("LOG_FILE_ENCRYPT_RECIPIENTS"); recipients != "" {
+		config.FileOptions.EncryptRecipients = strings.Split(recipients, ",")
+	}
+	if interval := os.Getenv("LOG_FILE_REOPEN_INTERVAL"); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil {
+			config.FileOptions.ReopenInterval = d
+		} else {
+			config.invalidEnv("LOG_FILE_REOPEN_INTERVAL", interval, err)
+		}
+	}
+	if lock := os.Getenv("LOG_FILE_LOCK"); lock != "" {
+		config.FileOptions.Lock = strings.ToLower(lock) == "true"
+	}
 	if createDir := os.Getenv("LOG_FILE_CREATE_DIR"); createDir != "" {
 		config.FileOptions.CreateDir = strings.ToLower(createDir) == "true"
 	}
@@ -77,8 +339,45 @@ func ConfigFromEnv() Config {
 	if timeFormat := os.Getenv("LOG_FILE_TIME_FORMAT"); timeFormat != "" {
 		config.FileOptions.TimeRotationFormat = timeFormat
 	}
+	if rotationTime := os.Getenv("LOG_FILE_ROTATION_TIME"); rotationTime != "" {
+		config.FileOptions.RotationTime = rotationTime
+	}
+	if weekStart := os.Getenv("LOG_FILE_WEEK_START_DAY"); weekStart != "" {
+		config.FileOptions.WeekStartDay = strings.ToLower(weekStart)
+	}
+	if symlink := os.Getenv("LOG_FILE_SYMLINK"); symlink != "" {
+		config.FileOptions.Symlink = strings.ToLower(symlink) == "true"
+	}
+	if policy := os.Getenv("LOG_FILE_DISK_FULL_POLICY"); policy != "" {
+		config.FileOptions.DiskFullPolicy = strings.ToLower(policy)
+	}
+	if command := os.Getenv("LOG_FILE_ON_ROTATE_COMMAND"); command != "" {
+		config.FileOptions.OnRotateCommand = strings.Fields(command)
+	}
+	if prefix := os.Getenv("LOG_FILE_ARCHIVE_PREFIX"); prefix != "" {
+		config.FileOptions.Archive.KeyPrefix = prefix
+	}
+	if deleteLocal := os.Getenv("LOG_FILE_ARCHIVE_DELETE_LOCAL"); deleteLocal != "" {
+		config.FileOptions.Archive.DeleteLocal = strings.ToLower(deleteLocal) == "true"
+	}
+	if bufferSize := os.Getenv("LOG_FILE_BUFFER_SIZE"); bufferSize != "" {
+		if size, err := strconv.Atoi(bufferSize); err == nil {
+			config.FileOptions.BufferSize = size
+		} else {
+			config.invalidEnv("LOG_FILE_BUFFER_SIZE", bufferSize, err)
+		}
+	}
+	if flushInterval := os.Getenv("LOG_FILE_FLUSH_INTERVAL"); flushInterval != "" {
+		if d, err := time.ParseDuration(flushInterval); err == nil {
+			config.FileOptions.FlushInterval = d
+		} else {
+			config.invalidEnv("LOG_FILE_FLUSH_INTERVAL", flushInterval, err)
+		}
+	}
 
-	// Adjust config based on environment
+	// Adjust config based on environment. An explicit LOG_ENCODING is kept
+	// outside production, which always encodes json.
+	encodingSet := os.Getenv("LOG_ENCODING") != ""
 	switch config.Environment {
 	case EnvProduction:
 		if config.Level == "" {
@@ -89,25 +388,56 @@ func ConfigFromEnv() Config {
 		if config.Level == "" {
 			config.Level = LevelInfo
 		}
-		config.Encoding = EncodingJSON
+		if !encodingSet {
+			config.Encoding = EncodingJSON
+		}
 	case EnvTest:
 		if config.Level == "" {
 			config.Level = LevelError
 		}
-		config.Encoding = EncodingConsole
+		if !encodingSet {
+			config.Encoding = EncodingConsole
+		}
 	default: // development
 		if config.Level == "" {
 			config.Level = LevelDebug
 		}
-		config.Encoding = EncodingConsole
+		if !encodingSet {
+			config.Encoding = EncodingConsole
+		}
 	}
 
 	return config
 }
 
+// parseLevelSpec parses a comma separated list of name=level pairs. The name
+// "*" sets the root level. Malformed pairs are ignored.
+func parseLevelSpec(spec string) (root string, levels map[string]string) {
+	levels = map[string]string{}
+	for _, pair := range strings.Split(spec, ",") {
+		name, level, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		name = strings.TrimSpace(name)
+		level = strings.ToLower(strings.TrimSpace(level))
+		if !ok || name == "" || level == "" {
+			continue
+		}
+		if name == "*" {
+			root = level
+			continue
+		}
+		levels[name] = level
+	}
+	return root, levels
+}
+
+// invalidEnv records an environment variable that could not be parsed, to
+// be reported by ValidateStrict
+func (c *Config) invalidEnv(name, value string, err error) {
+	c.envErrors = append(c.envErrors, fmt.Errorf("logger: invalid %s %q: %w", name, value, err))
+}
+
 // GetEffectiveConfig returns the effective configuration after applying defaults and validation
 func GetEffectiveConfig() Config {
-	config := ConfigFromEnv()
-	config.Validate()
+	config, _ := ConfigFromEnv().Normalize()
 	return config
 }
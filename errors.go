@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Err creates the "error" field, like zap.Error
+func Err(err error) zap.Field {
+	return zap.NamedError("error", err)
+}
+
+// ErrDetails creates the "error" field of Err followed, when the error
+// carries more detail, by "error_verbose" (the %+v rendering of the first
+// error in the chain that has one, e.g. a pkg/errors stack trace wrapped
+// with %w) and "error_causes" (the messages of the wrapped causes,
+// outermost first). Being separate fields, they can be filtered or
+// redacted like any other.
+//
+//	log.Error("query failed", logger.ErrDetails(err)...)
+func ErrDetails(err error) []zap.Field {
+	if err == nil {
+		return nil
+	}
+	// The verbose rendering is its own field, so zap must not add errorVerbose
+	fields := []zap.Field{zap.NamedError("error", plainError{err})}
+	if verbose := errorVerbose(err); verbose != "" {
+		fields = append(fields, zap.String("error_verbose", verbose))
+	}
+	if causes := errorCauses(err); len(causes) > 0 {
+		fields = append(fields, zap.Strings("error_causes", causes))
+	}
+	return fields
+}
+
+// plainError hides the fmt.Formatter and error group methods of an error
+// from zap, which would otherwise add errorVerbose and errorCauses fields
+type plainError struct {
+	err error
+}
+
+func (e plainError) Error() string { return e.err.Error() }
+
+func (e plainError) Unwrap() error { return e.err }
+
+// errorVerbose returns the %+v rendering of the first error in the chain of
+// err that formats more than its message, or empty if there is none
+func errorVerbose(err error) string {
+	for i := 0; err != nil && i < maxErrorCauses; i++ {
+		if _, ok := err.(fmt.Formatter); ok {
+			if verbose := fmt.Sprintf("%+v", err); verbose != err.Error() {
+				return verbose
+			}
+		}
+		err = unwrapCause(err)
+	}
+	return ""
+}
+
+// errorCauses returns the messages of the errors wrapped by err, following
+// both fmt %w chains and pkg/errors style Cause methods
+func errorCauses(err error) []string {
+	var causes []string
+	for i := 0; i < maxErrorCauses; i++ {
+		next := unwrapCause(err)
+		if next == nil {
+			break
+		}
+		causes = append(causes, next.Error())
+		err = next
+	}
+	return causes
+}
+
+// maxErrorCauses bounds the cause chain in case of cyclic wrappers
+const maxErrorCauses = 32
+
+func unwrapCause(err error) error {
+	if next := errors.Unwrap(err); next != nil {
+		return next
+	}
+	if causer, ok := err.(interface{ Cause() error }); ok {
+		if next := causer.Cause(); next != err {
+			return next
+		}
+	}
+	return nil
+}
+
+// Errors creates an array field with the message of each non-nil error
+func Errors(key string, errs []error) zap.Field {
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+	return zap.Array(key, stringArray(msgs))
+}
+
+// ErrorList creates an array field from an error composed of other errors
+// (errors.Join, multierr, or any error with Unwrap() []error), flattening
+// nested groups. A plain error yields a single element.
+func ErrorList(key string, err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return Errors(key, flattenErrors(err, nil))
+}
+
+// flattenErrors appends the leaf errors of a possibly nested error group
+func flattenErrors(err error, out []error) []error {
+	var group []error
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		group = e.Unwrap()
+	case interface{ Errors() []error }:
+		group = e.Errors()
+	default:
+		return append(out, err)
+	}
+	for _, inner := range group {
+		if inner != nil {
+			out = flattenErrors(inner, out)
+		}
+	}
+	return out
+}
+
+// stringArray encodes a string slice as an array
+type stringArray []string
+
+func (a stringArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, s := range a {
+		enc.AppendString(s)
+	}
+	return nil
+}
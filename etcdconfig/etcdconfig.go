@@ -0,0 +1,63 @@
+// Package etcdconfig applies logger settings stored in an etcd key and
+// follows its changes, so that the level and sampling of a whole fleet can
+// be changed within seconds without redeploying. The key holds a JSON
+// document:
+//
+//	{"level": "debug", "levels": {"db": "warn"}, "sampling": {"enabled": true, "initial": 100, "thereafter": 10, "tick": "1s"}}
+//
+// Omitted settings are left unchanged. Usage:
+//
+//	client, err := clientv3.New(clientv3.Config{Endpoints: []string{"etcd:2379"}})
+//	if err != nil {
+//		return err
+//	}
+//	go etcdconfig.Watch(ctx, client, "/config/logger/billing", nil)
+package etcdconfig
+
+import (
+	"context"
+
+	"github.com/csmart-libs/go-logger"
+	"github.com/csmart-libs/go-logger/internal/remoteconfig"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Client is the part of the etcd API used by Watch, implemented by
+// *clientv3.Client
+type Client interface {
+	Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error)
+	Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan
+}
+
+// Watch applies the settings stored in key to l, or to the global logger if
+// l is nil, then applies every change until ctx is done. Invalid documents
+// are logged and skipped. It returns the error that stopped the watch, or
+// the error of ctx.
+func Watch(ctx context.Context, client Client, key string, l logger.Logger) error {
+	applier, err := remoteconfig.New(l)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if len(resp.Kvs) > 0 {
+		applier.Update("etcd:"+key, resp.Kvs[0].Value)
+	}
+
+	watch := client.Watch(clientv3.WithRequireLeader(ctx), key, clientv3.WithRev(resp.Header.Revision+1))
+	for wresp := range watch {
+		if err := wresp.Err(); err != nil {
+			applier.Warn("etcd logger settings watch failed", err)
+			return err
+		}
+		for _, ev := range wresp.Events {
+			if ev.Type == clientv3.EventTypePut {
+				applier.Update("etcd:"+key, ev.Kv.Value)
+			}
+		}
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"expvar"
+	"time"
+)
+
+// expvarStats publishes logger statistics as an expvar map, so they show up
+// on the /debug/vars endpoint:
+//
+//	{"level": "info", "entries": {"info": 12}, "dropped": {}, "bytes_written": {"stdout": 1830},
+//	 "sink_errors": {}, "last_sink_error": "", "rotations": 0, "last_rotation": ""}
+type expvarStats struct {
+	entries       *expvar.Map
+	dropped       *expvar.Map
+	bytesWritten  *expvar.Map
+	sinkErrors    *expvar.Map
+	lastSinkError *expvar.String
+	rotations     *expvar.Int
+	lastRotation  *expvar.String
+}
+
+// newExpvarStats publishes the statistics of a logger under name. A logger
+// created later with the same name replaces the published statistics.
+func newExpvarStats(name string, levels *namedLevels) *expvarStats {
+	root, ok := expvar.Get(name).(*expvar.Map)
+	if ok {
+		root.Init()
+	} else {
+		root = expvar.NewMap(name)
+	}
+
+	s := &expvarStats{
+		entries:       new(expvar.Map).Init(),
+		dropped:       new(expvar.Map).Init(),
+		bytesWritten:  new(expvar.Map).Init(),
+		sinkErrors:    new(expvar.Map).Init(),
+		lastSinkError: new(expvar.String),
+		rotations:     new(expvar.Int),
+		lastRotation:  new(expvar.String),
+	}
+	root.Set("level", expvar.Func(func() any { return levelName(levels.Level("")) }))
+	root.Set("entries", s.entries)
+	root.Set("dropped", s.dropped)
+	root.Set("bytes_written", s.bytesWritten)
+	root.Set("sink_errors", s.sinkErrors)
+	root.Set("last_sink_error", s.lastSinkError)
+	root.Set("rotations", s.rotations)
+	root.Set("last_rotation", s.lastRotation)
+	return s
+}
+
+func (s *expvarStats) RecordEntry(level, _ string) {
+	s.entries.Add(level, 1)
+}
+
+func (s *expvarStats) RecordWrite(sink string, bytes int, _ time.Duration, err error) {
+	s.bytesWritten.Add(sink, int64(bytes))
+	if err != nil {
+		s.sinkErrors.Add(sink, 1)
+		s.lastSinkError.Set(err.Error())
+	}
+}
+
+func (s *expvarStats) RecordDropped(reason string, n int) {
+	s.dropped.Add(reason, int64(n))
+}
+
+func (s *expvarStats) RecordRotation(_ string, at time.Time) {
+	s.rotations.Add(1)
+	s.lastRotation.Set(at.Format(time.RFC3339))
+}
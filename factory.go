@@ -1,120 +1,348 @@
 package logger
 
 import (
+	"context"
+	"errors"
 	"io"
 	"os"
-	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-// Global logger instance
-var globalLogger Logger
+// globalState is the global logger along with its variant skipping the
+// package-level logging functions in caller annotations
+type globalState struct {
+	logger Logger
+	caller Logger
+}
+
+// Global logger instance, swapped atomically so that it can be replaced
+// while other goroutines log
+var global atomic.Pointer[globalState]
+
+// globalDefault creates the default global logger at most once
+var globalDefault sync.Once
 
-// Initialize initializes the global logger with the given configuration
-func Initialize(config Config) error {
-	logger, err := NewLogger(config)
+var errNotZapLogger = errors.New("logger: global logger is not a *ZapLogger")
+
+// Initialize initializes the global logger with the given configuration and
+// additional zap options. It can be called again, also concurrently with
+// logging, to replace the global logger; the previous one is not closed,
+// since it may still be in use.
+func Initialize(config Config, opts ...zap.Option) error {
+	logger, err := NewLogger(config, opts...)
 	if err != nil {
 		return err
 	}
-	globalLogger = logger
+	SetLogger(logger)
 	return nil
 }
 
-// NewLogger creates a new logger instance with the given configuration
-func NewLogger(config Config) (Logger, error) {
-	// Parse log level
-	level, err := zapcore.ParseLevel(config.Level)
-	if err != nil {
-		level = zapcore.InfoLevel
+// SetLogger replaces the global logger with l, e.g. a logger built with
+// NewLoggerWithCores or a custom Logger implementation. A nil l discards
+// all entries, like Nop.
+func SetLogger(l Logger) {
+	if l == nil {
+		l = Nop()
+	}
+	global.Store(newGlobalState(l))
+}
+
+func newGlobalState(l Logger) *globalState {
+	return &globalState{logger: l, caller: ApplyOptions(l, AddCallerSkip(1))}
+}
+
+// NewLogger creates a new logger instance with the given configuration.
+// The zap options are applied after the ones derived from the configuration,
+// e.g. zap.AddCallerSkip, zap.Development, zap.WithClock or zap.WrapCore.
+func NewLogger(config Config, opts ...zap.Option) (Logger, error) {
+	return newLogger(config, buildOptions{zapOptions: opts})
+}
+
+// NewLoggerWithCores creates a logger that also writes to the given cores,
+// e.g. tracing exporters or in-house shippers. Entries reach the extra cores
+// after the configured levels, sampling, redaction and hooks, like the
+// configured outputs; custom levels appear as their severity.
+func NewLoggerWithCores(config Config, extra ...zapcore.Core) (Logger, error) {
+	return newLogger(config, buildOptions{extra: extra})
+}
+
+// NewLoggerWithWriter creates a logger writing to w instead of the configured
+// outputs, e.g. a bytes.Buffer in unit tests or a device in embedded
+// environments. Options for the writer go in Config.Sinks under SinkWriter.
+func NewLoggerWithWriter(config Config, w io.Writer, opts ...zap.Option) (Logger, error) {
+	return newLogger(config, buildOptions{writer: zapcore.AddSync(w), zapOptions: opts})
+}
+
+// buildOptions holds what the constructors add to the configuration
+type buildOptions struct {
+	// writer replaces the configured outputs
+	writer zapcore.WriteSyncer
+
+	// extra cores are teed with the outputs
+	extra []zapcore.Core
+
+	// zapOptions are applied after the options derived from the configuration
+	zapOptions []zap.Option
+}
+
+// newLogger creates a logger from the configuration and build options
+func newLogger(config Config, build buildOptions) (_ Logger, err error) {
+	if config.StrictValidation {
+		if errs := config.ValidateStrict(); len(errs) > 0 {
+			return nil, errors.Join(errs...)
+		}
 	}
+	// Apply the defaults and replacements of Normalize, e.g. production
+	// always encodes JSON unless a sink asks for another encoding
+	config, _ = config.Normalize()
 
-	// Create encoder config based on environment
-	var encoderConfig zapcore.EncoderConfig
-	if config.Environment == "production" {
-		encoderConfig = zap.NewProductionEncoderConfig()
-		config.Encoding = "json"
-	} else {
-		encoderConfig = zap.NewDevelopmentEncoderConfig()
-		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	// Parse log level, valid once normalized
+	level, _ := ParseLevel(config.Level)
+
+	// Create encoders
+	encoders := newEncoderSet(config)
+	if _, err := encoders.get(config.Encoding); err != nil {
+		return nil, err
 	}
 
-	// Configure time encoding
-	encoderConfig.TimeKey = "timestamp"
-	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	// Create level registry for the root and named loggers
+	levels, err := newNamedLevels(level, config.Levels)
+	if err != nil {
+		return nil, err
+	}
 
-	// Create encoder
-	var encoder zapcore.Encoder
-	if config.Encoding == "json" {
-		encoder = zapcore.NewJSONEncoder(encoderConfig)
-	} else {
-		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	// Publish statistics through expvar
+	if config.Expvar != "" {
+		config.Metrics = combineMetrics(config.Metrics, newExpvarStats(config.Expvar, levels))
 	}
 
-	// Create writer syncer
-	var writeSyncer zapcore.WriteSyncer
+	// Open outputs
+	sinks := []sink{{name: SinkWriter, writer: build.writer}}
+	if build.writer == nil {
+		if sinks, err = newSinks(config); err != nil {
+			return nil, err
+		}
+	}
 
-	// Check if we need file output
-	if config.FileOptions.Filename != "" {
-		// Create directory if needed
-		if config.FileOptions.CreateDir {
-			dir := filepath.Dir(config.FileOptions.Filename)
-			if err := os.MkdirAll(dir, 0755); err != nil {
-				return nil, err
+	// Close the outputs opened so far if a later step fails
+	var closers, rotators []func() error
+	defer func() {
+		if err != nil {
+			for _, close := range closers {
+				_ = close()
 			}
 		}
+	}()
+	for _, s := range sinks {
+		if s.close != nil {
+			closers = append(closers, s.close)
+		}
+		if s.rotate != nil {
+			rotators = append(rotators, s.rotate)
+		}
+	}
 
-		var fileWriter io.Writer
-
-		// Choose writer based on rotation mode
-		switch config.FileOptions.RotationMode {
-		case RotationModeTime, RotationModeBoth:
-			// Use time-based rotating writer
-			fileWriter = NewTimeRotatingWriter(config.FileOptions)
-		default:
-			// Use size-based rotating writer (lumberjack)
-			fileWriter = &lumberjack.Logger{
-				Filename:   config.FileOptions.Filename,
-				MaxSize:    config.FileOptions.MaxSize,
-				MaxAge:     config.FileOptions.MaxAge,
-				MaxBackups: config.FileOptions.MaxBackups,
-				LocalTime:  config.FileOptions.LocalTime,
-				Compress:   config.FileOptions.Compress,
-			}
+	// Open the audit file, written apart from the processing stages
+	var audit *auditLogger
+	if config.Audit.File.Filename != "" {
+		var auditSink sink
+		if audit, auditSink, err = newAuditLogger(config, encoders, staticFields(config)); err != nil {
+			return nil, err
+		}
+		if auditSink.close != nil {
+			closers = append(closers, auditSink.close)
+		}
+		if auditSink.rotate != nil {
+			rotators = append(rotators, auditSink.rotate)
 		}
+	}
 
-		// Combine stdout and file output if needed
-		if len(config.OutputPaths) > 0 && config.OutputPaths[0] != "stdout" {
-			// Only file output
-			writeSyncer = zapcore.AddSync(fileWriter)
-		} else {
-			// Both stdout and file output
-			writeSyncer = zapcore.NewMultiWriteSyncer(
-				zapcore.AddSync(os.Stdout),
-				zapcore.AddSync(fileWriter),
-			)
+	// Measure output volume for the circuit breaker
+	var breaker *volumeBreaker
+	if config.CircuitBreaker.Enabled {
+		breaker = newVolumeBreaker(config.CircuitBreaker, config.metrics(), config.internalErrorHandler())
+		for i := range sinks {
+			sinks[i].writer = breaker.writer(sinks[i].writer)
 		}
-	} else {
-		// Only stdout output
-		writeSyncer = zapcore.AddSync(os.Stdout)
 	}
 
-	// Create core
-	core := zapcore.NewCore(encoder, writeSyncer, level)
+	// Measure writes to the sinks
+	if config.Metrics != nil {
+		for i := range sinks {
+			sinks[i].writer = newMetricsWriter(sinks[i].name, sinks[i].writer, config.Metrics)
+		}
+	}
+
+	// Create core. Levels are enforced by the named level core so that named
+	// loggers can enable levels below the root level.
+	// Compile the drop filters, level overrides and routes, which can then
+	// change at runtime
+	rules, err := newRuleSet(config)
+	if err != nil {
+		return nil, err
+	}
+	cores := make([]zapcore.Core, 0, len(sinks)+len(build.extra))
+	for _, s := range sinks {
+		options := config.Sinks[s.name]
+		encoder, err := encoders.get(options.Encoding)
+		if err != nil {
+			return nil, err
+		}
+		sinkCore, err := newSinkCore(encoder, s, options)
+		if err != nil {
+			return nil, err
+		}
+		cores = append(cores, newRouteCore(sinkCore, rules, s.name))
+	}
+	cores = append(cores, build.extra...)
+	core := zapcore.NewTee(cores...)
+	var tenants *tenantRouter
+	if config.Tenants.Field != "" {
+		if tenants, err = newTenantRouter(config, encoders); err != nil {
+			return nil, err
+		}
+		core = newTenantCore(core, tenants)
+	}
+	if config.Metrics != nil {
+		core = newMetricsCore(core, config.Metrics)
+	}
+	if tenants != nil {
+		closers = append(closers, tenants.close)
+		rotators = append(rotators, tenants.rotate)
+	}
+	if config.Async.Enabled {
+		queue := newAsyncQueue(config.Async, config.internalErrorHandler(), config.metrics())
+		core = newAsyncCore(core, queue)
+		closers = append(closers, queue.close)
+	}
+	// Sampling is the innermost stage; its options can change at runtime
+	core, sampling := newDynamicSamplingCore(core, config.Sampling, config.metrics())
+	core, err = wrapCore(config, core, levels, rules)
+	if err != nil {
+		return nil, err
+	}
+	if breaker != nil {
+		core = newBreakerCore(core, breaker)
+	}
 
 	// Create logger
-	zapLogger := zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
+	// Skip the ZapLogger method so that entries carry the caller of the logger
+	options := []zap.Option{zap.AddCallerSkip(1)}
+	if config.callerEnabled() {
+		options = append(options, zap.AddCaller())
+	}
+	if !config.DisableStacktrace {
+		// Custom levels take the stack traces of their severity
+		options = append(options, zap.AddStacktrace(zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+			return severityOf(l) >= zapcore.ErrorLevel
+		})))
+	}
+	if config.OnInternalError != nil {
+		options = append(options, zap.ErrorOutput(internalErrorWriter{report: config.OnInternalError}))
+	}
+	if fields := staticFields(config); len(fields) > 0 {
+		options = append(options, zap.Fields(fields...))
+	}
+	if config.ExitFunc != nil {
+		options = append(options, zap.WithFatalHook(exitHook{exit: config.ExitFunc}))
+	}
+	options = append(options, build.zapOptions...)
+	zapLogger := zap.New(core, options...)
+
+	exit := config.ExitFunc
+	if exit == nil {
+		exit = os.Exit
+	}
+	return &ZapLogger{
+		logger:   zapLogger,
+		levels:   levels,
+		closers:  closers,
+		rotators: rotators,
+		audit:    audit,
+		rules:    rules,
+		sampling: sampling,
+		repanic:  config.RepanicOnRecover,
+		exit:     exit,
+		report:   config.internalErrorHandler(),
+	}, nil
+}
+
+// exitHook calls Config.ExitFunc, or os.Exit for custom fatal levels, after
+// a fatal entry is written
+type exitHook struct {
+	exit func(code int)
+}
+
+func (h exitHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+	h.exit(1)
+}
+
+// staticFields returns the fields added to every entry: the initial,
+// environment and enrichment fields
+func staticFields(config Config) []zap.Field {
+	fields := Fields(config.InitialFields)
+	fields = append(fields, envFields(config.EnvFields)...)
+	return append(fields, enrichmentFields(config.Enrichment)...)
+}
 
-	return &ZapLogger{logger: zapLogger}, nil
+// wrapCore applies the configured processing stages to the output core. The
+// stages are listed innermost first; entries pass through them in reverse.
+func wrapCore(config Config, core zapcore.Core, levels *namedLevels, rules *RuleSet) (zapcore.Core, error) {
+	// Stages below the custom level core only see standard levels
+	core = newCustomLevelCore(core)
+	if config.RateLimit.Enabled && config.RateLimit.Rate > 0 {
+		core = newRateLimitCore(core, config.RateLimit, config.metrics())
+	}
+	if config.Dedup.Enabled {
+		core = newDedupCore(core, config.Dedup, config.internalErrorHandler())
+	}
+	if len(config.Scrubbing.Presets) > 0 || len(config.Scrubbing.Patterns) > 0 {
+		var err error
+		if core, err = newScrubbingCore(core, config.Scrubbing); err != nil {
+			return nil, err
+		}
+	}
+	if config.Redaction.Enabled {
+		core = newRedactionCore(core, config.Redaction)
+	}
+	if config.Enrichment.GoroutineID {
+		core = newGoroutineCore(core)
+	}
+	core = newDropCore(core, rules, config.metrics())
+	if len(config.Hooks) > 0 {
+		core = newHookCore(core, config.Hooks)
+	}
+	core = newLevelOverrideCore(core, rules, levels)
+	if config.Backtrace.Enabled {
+		return newBacktraceCore(core, levels, config.Backtrace), nil
+	}
+	return newNamedLevelCore(core, levels), nil
 }
 
 // GetLogger returns the global logger instance
 func GetLogger() Logger {
-	if globalLogger == nil {
-		// Initialize with default config if not initialized
+	return loadGlobal().logger
+}
+
+// callerLogger returns the global logger to use from the package-level
+// logging functions
+func callerLogger() Logger {
+	return loadGlobal().caller
+}
+
+// loadGlobal returns the global logger, initializing it with the default
+// config if neither Initialize nor SetLogger was called
+func loadGlobal() *globalState {
+	if g := global.Load(); g != nil {
+		return g
+	}
+	globalDefault.Do(func() {
 		config := DefaultConfig()
 		if env := os.Getenv("APP_ENV"); env != "" {
 			config.Environment = env
@@ -122,41 +350,265 @@ func GetLogger() Logger {
 		if level := os.Getenv("LOG_LEVEL"); level != "" {
 			config.Level = strings.ToLower(level)
 		}
-		Initialize(config)
-	}
-	return globalLogger
+		logger, err := NewLogger(config)
+		if err != nil {
+			logger = Nop()
+		}
+		// Keep a logger set concurrently by Initialize or SetLogger
+		global.CompareAndSwap(nil, newGlobalState(logger))
+	})
+	return global.Load()
 }
 
 // Global logger functions
 
+// Named creates a named child of the global logger
+func Named(name string) Logger {
+	return GetLogger().Named(name)
+}
+
+// SetLevel changes the root level of the global logger at runtime
+func SetLevel(level string) error {
+	zl, ok := GetLogger().(*ZapLogger)
+	if !ok {
+		return errNotZapLogger
+	}
+	return zl.SetLevel(level)
+}
+
+// SetNamedLevel changes the level of a named logger of the global logger at runtime
+func SetNamedLevel(name, level string) error {
+	zl, ok := GetLogger().(*ZapLogger)
+	if !ok {
+		return errNotZapLogger
+	}
+	return zl.SetNamedLevel(name, level)
+}
+
+// SetSampling changes the sampling options of the global logger at runtime
+func SetSampling(options SamplingOptions) error {
+	zl, ok := GetLogger().(*ZapLogger)
+	if !ok {
+		return errNotZapLogger
+	}
+	zl.SetSampling(options)
+	return nil
+}
+
+// Unwrap returns the zap logger underlying the global logger (see
+// (*ZapLogger).Unwrap), or a no-op zap logger if it is not a *ZapLogger
+func Unwrap() *zap.Logger {
+	if zl, ok := GetLogger().(*ZapLogger); ok {
+		return zl.Unwrap()
+	}
+	return zap.NewNop()
+}
+
+// Sugar returns a zap SugaredLogger over the global logger (see
+// (*ZapLogger).Sugar), or a no-op one if it is not a *ZapLogger
+func Sugar() *zap.SugaredLogger {
+	return Unwrap().Sugar()
+}
+
+// EffectiveLevel returns the level the global logger applies to a logger name
+func EffectiveLevel(name string) string {
+	zl, ok := GetLogger().(*ZapLogger)
+	if !ok {
+		return ""
+	}
+	return zl.EffectiveLevel(name)
+}
+
+// Rules returns the runtime rules of the global logger, or nil if it is
+// not a *ZapLogger; Add on a nil RuleSet returns an error
+func Rules() *RuleSet {
+	if zl, ok := GetLogger().(*ZapLogger); ok {
+		return zl.Rules()
+	}
+	return nil
+}
+
 // Debug logs a debug message
 func Debug(msg string, fields ...zap.Field) {
-	GetLogger().Debug(msg, fields...)
+	callerLogger().Debug(msg, fields...)
 }
 
 // Info logs an info message
 func Info(msg string, fields ...zap.Field) {
-	GetLogger().Info(msg, fields...)
+	callerLogger().Info(msg, fields...)
 }
 
 // Warn logs a warning message
 func Warn(msg string, fields ...zap.Field) {
-	GetLogger().Warn(msg, fields...)
+	callerLogger().Warn(msg, fields...)
 }
 
 // Error logs an error message
 func Error(msg string, fields ...zap.Field) {
-	GetLogger().Error(msg, fields...)
+	callerLogger().Error(msg, fields...)
 }
 
 // Fatal logs a fatal message and exits
 func Fatal(msg string, fields ...zap.Field) {
-	GetLogger().Fatal(msg, fields...)
+	callerLogger().Fatal(msg, fields...)
 }
 
 // Panic logs a panic message and panics
 func Panic(msg string, fields ...zap.Field) {
-	GetLogger().Panic(msg, fields...)
+	callerLogger().Panic(msg, fields...)
+}
+
+// DebugContext logs a debug message with the fields carried by ctx
+func DebugContext(ctx context.Context, msg string, fields ...zap.Field) {
+	l := callerLogger()
+	if cl, ok := l.(ContextLogger); ok {
+		cl.DebugContext(ctx, msg, fields...)
+		return
+	}
+	l.Debug(msg, withContextFields(ctx, fields)...)
+}
+
+// InfoContext logs an info message with the fields carried by ctx
+func InfoContext(ctx context.Context, msg string, fields ...zap.Field) {
+	l := callerLogger()
+	if cl, ok := l.(ContextLogger); ok {
+		cl.InfoContext(ctx, msg, fields...)
+		return
+	}
+	l.Info(msg, withContextFields(ctx, fields)...)
+}
+
+// WarnContext logs a warning message with the fields carried by ctx
+func WarnContext(ctx context.Context, msg string, fields ...zap.Field) {
+	l := callerLogger()
+	if cl, ok := l.(ContextLogger); ok {
+		cl.WarnContext(ctx, msg, fields...)
+		return
+	}
+	l.Warn(msg, withContextFields(ctx, fields)...)
+}
+
+// ErrorContext logs an error message with the fields carried by ctx
+func ErrorContext(ctx context.Context, msg string, fields ...zap.Field) {
+	l := callerLogger()
+	if cl, ok := l.(ContextLogger); ok {
+		cl.ErrorContext(ctx, msg, fields...)
+		return
+	}
+	l.Error(msg, withContextFields(ctx, fields)...)
+}
+
+// Log logs a message at the named standard or custom level
+func Log(level string, msg string, fields ...zap.Field) {
+	callerLogger().Log(level, msg, fields...)
+}
+
+// Check returns a CheckedEntry if the global logger would log an entry at
+// level, or nil otherwise. A global logger that does not implement
+// CheckLogger gets an entry that is always written, through its Log method.
+func Check(level string, msg string) *zapcore.CheckedEntry {
+	l := callerLogger()
+	if cl, ok := l.(CheckLogger); ok {
+		return cl.Check(level, msg)
+	}
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		lvl = zapcore.InfoLevel
+	}
+	ent := zapcore.Entry{Level: lvl, Message: msg, Time: time.Now()}
+	return (*zapcore.CheckedEntry)(nil).AddCore(ent, logCore{l: l, level: level})
+}
+
+// logCore writes the entries checked by Check through the Log method of a
+// logger that does not implement CheckLogger
+type logCore struct {
+	l     Logger
+	level string
+}
+
+func (c logCore) Enabled(zapcore.Level) bool        { return true }
+func (c logCore) With([]zapcore.Field) zapcore.Core { return c }
+func (c logCore) Sync() error                       { return nil }
+
+func (c logCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+func (c logCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.l.Log(c.level, ent.Message, fields...)
+	return nil
+}
+
+// Enabled reports whether the global logger would log an entry at level.
+// It is true for a global logger that does not implement LevelEnabler.
+func Enabled(level string) bool {
+	if l, ok := GetLogger().(LevelEnabler); ok {
+		return l.Enabled(level)
+	}
+	return true
+}
+
+// Level returns the effective level of the global logger, or empty if it
+// does not implement LevelEnabler
+func Level() string {
+	if l, ok := GetLogger().(LevelEnabler); ok {
+		return l.Level()
+	}
+	return ""
+}
+
+// Audit writes an audit event through the global logger
+func Audit(event string, fields ...zap.Field) error {
+	if zl, ok := callerLogger().(*ZapLogger); ok {
+		return zl.Audit(event, fields...)
+	}
+	callerLogger().Info(event, fields...)
+	return nil
+}
+
+// SecAuthSuccess logs a successful authentication through the global logger
+func SecAuthSuccess(actor, target string, fields ...zap.Field) {
+	if zl, ok := callerLogger().(*ZapLogger); ok {
+		zl.SecAuthSuccess(actor, target, fields...)
+		return
+	}
+	secAuthSuccess.logTo(callerLogger(), actor, target, fields)
+}
+
+// SecAuthFailure logs a failed authentication through the global logger
+func SecAuthFailure(actor, target string, fields ...zap.Field) {
+	if zl, ok := callerLogger().(*ZapLogger); ok {
+		zl.SecAuthFailure(actor, target, fields...)
+		return
+	}
+	secAuthFailure.logTo(callerLogger(), actor, target, fields)
+}
+
+// SecAccessDenied logs a denied access through the global logger
+func SecAccessDenied(actor, target string, fields ...zap.Field) {
+	if zl, ok := callerLogger().(*ZapLogger); ok {
+		zl.SecAccessDenied(actor, target, fields...)
+		return
+	}
+	secAccessDenied.logTo(callerLogger(), actor, target, fields)
+}
+
+// SecConfigChange logs a configuration change through the global logger
+func SecConfigChange(actor, target string, fields ...zap.Field) {
+	if zl, ok := callerLogger().(*ZapLogger); ok {
+		zl.SecConfigChange(actor, target, fields...)
+		return
+	}
+	secConfigChange.logTo(callerLogger(), actor, target, fields)
+}
+
+// SecPrivilegeChange logs a change of roles or permissions through the global logger
+func SecPrivilegeChange(actor, target string, fields ...zap.Field) {
+	if zl, ok := callerLogger().(*ZapLogger); ok {
+		zl.SecPrivilegeChange(actor, target, fields...)
+		return
+	}
+	secPrivilegeChange.logTo(callerLogger(), actor, target, fields)
 }
 
 // With creates a child logger with additional fields
@@ -164,7 +616,48 @@ func With(fields ...zap.Field) Logger {
 	return GetLogger().With(fields...)
 }
 
+// WithFields creates a child logger with fields from a map
+func WithFields(fields map[string]any) Logger {
+	return GetLogger().WithFields(fields)
+}
+
+// WithOptions creates a child logger of the global logger with the options
+// applied
+func WithOptions(opts ...Option) Logger {
+	return ApplyOptions(GetLogger(), opts...)
+}
+
 // Sync flushes any buffered log entries
 func Sync() error {
 	return GetLogger().Sync()
 }
+
+// Close flushes the global logger and releases its background resources
+func Close() error {
+	if zl, ok := GetLogger().(*ZapLogger); ok {
+		return zl.Close()
+	}
+	return GetLogger().Sync()
+}
+
+// Rotate forces a rotation of the log files of the global logger
+func Rotate() error {
+	if zl, ok := GetLogger().(*ZapLogger); ok {
+		return zl.Rotate()
+	}
+	return errNoRotatingFile
+}
+
+// RotateOnSignal rotates the log files of the global logger whenever one of
+// the signals is received, SIGUSR1 by default. Call stop to stop handling
+// them. Rotation errors go to the internal error handler of the global
+// logger.
+func RotateOnSignal(sigs ...os.Signal) (stop func()) {
+	return rotateOnSignal(Rotate, func(err error) {
+		if zl, ok := GetLogger().(*ZapLogger); ok && zl.report != nil {
+			zl.report(err)
+			return
+		}
+		Config{}.internalErrorHandler()(err)
+	}, sigs)
+}
@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// internalErrorHandler returns the function receiving the logger's own errors
+func (c Config) internalErrorHandler() func(error) {
+	if c.OnInternalError != nil {
+		return c.OnInternalError
+	}
+	return func(err error) {
+		fmt.Fprintf(os.Stderr, "%s %v\n", time.Now().Format(time.RFC3339), err)
+	}
+}
+
+// internalErrorWriter passes the lines zap writes to its error output, such
+// as encoding failures, to the internal error hook
+type internalErrorWriter struct {
+	report func(error)
+}
+
+func (w internalErrorWriter) Write(p []byte) (int, error) {
+	w.report(errors.New(strings.TrimSpace(string(p))))
+	return len(p), nil
+}
+
+func (w internalErrorWriter) Sync() error { return nil }
+
+// newFallbackOutput returns the name of the writer used while a sink fails
+// and the writer, which is nil when failing entries are dropped
+func newFallbackOutput(output string) (string, zapcore.WriteSyncer) {
+	switch strings.ToLower(output) {
+	case FallbackNone:
+		return FallbackNone, nil
+	case FallbackStdout:
+		return FallbackStdout, zapcore.AddSync(os.Stdout)
+	default:
+		return FallbackStderr, zapcore.AddSync(os.Stderr)
+	}
+}
+
+// fallbackWriter writes to the fallback output while the primary writer
+// returns errors. The primary is retried on every write; the first failure
+// and the recovery are reported to the internal error hook.
+type fallbackWriter struct {
+	name         string
+	primary      zapcore.WriteSyncer
+	fallbackName string
+	fallback     zapcore.WriteSyncer
+	report       func(error)
+	metrics      MetricsRecorder
+	failing      atomic.Bool
+}
+
+func newFallbackWriter(name string, primary zapcore.WriteSyncer, config Config) zapcore.WriteSyncer {
+	fallbackName, fallback := newFallbackOutput(config.FallbackOutput)
+	return &fallbackWriter{
+		name:         name,
+		primary:      primary,
+		fallbackName: fallbackName,
+		fallback:     fallback,
+		report:       config.internalErrorHandler(),
+		metrics:      config.metrics(),
+	}
+}
+
+func (w *fallbackWriter) Write(p []byte) (int, error) {
+	n, err := w.primary.Write(p)
+	if err == nil {
+		if w.failing.CompareAndSwap(true, false) {
+			w.report(fmt.Errorf("logger: %s sink recovered", w.name))
+		}
+		return n, nil
+	}
+
+	if w.failing.CompareAndSwap(false, true) {
+		if w.fallback != nil {
+			w.report(fmt.Errorf("logger: write to %s sink failed, falling back to %s: %w", w.name, w.fallbackName, err))
+		} else {
+			w.report(fmt.Errorf("logger: write to %s sink failed, dropping entries: %w", w.name, err))
+		}
+	}
+	if w.fallback == nil {
+		w.metrics.RecordDropped(DropReasonSinkFailed, 1)
+		return len(p), nil
+	}
+	return w.fallback.Write(p)
+}
+
+func (w *fallbackWriter) Sync() error {
+	if w.failing.Load() && w.fallback != nil {
+		return w.fallback.Sync()
+	}
+	return w.primary.Sync()
+}
@@ -0,0 +1,110 @@
+// Package fibermw provides Fiber middleware logging requests and recovered
+// panics through the logger package, with the same fields as ginmw.
+//
+//	app := fiber.New()
+//	app.Use(fibermw.Logger(), fibermw.Recovery())
+package fibermw
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/csmart-libs/go-logger"
+	"github.com/csmart-libs/go-logger/accesslog"
+	"github.com/csmart-libs/go-logger/internal/httplog"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Logger returns middleware logging each request with its method, path,
+// status, response bytes, latency, client IP and request ID through the
+// global logger
+func Logger() fiber.Handler {
+	return LoggerWith(nil)
+}
+
+// LoggerWith is like Logger but logs through l. A nil l uses the global
+// logger.
+func LoggerWith(l logger.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		if err := c.Next(); err != nil {
+			// Let the error handler write the response so that its status
+			// is logged
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+		httplog.Log(httplog.LoggerOrGlobal(l), request(c, start))
+		return nil
+	}
+}
+
+// AccessLog returns middleware writing an access log line for each request
+// to access, e.g. in the Apache combined format for legacy analyzers. It
+// runs alongside Logger.
+func AccessLog(access *accesslog.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		if err := c.Next(); err != nil {
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+		_ = access.Log(request(c, start).AccessEntry())
+		return nil
+	}
+}
+
+// Recovery returns middleware recovering from panics in later handlers,
+// logging them with their stack trace through the global logger and
+// returning a 500 error to the error handler
+func Recovery() fiber.Handler {
+	return RecoveryWith(nil)
+}
+
+// RecoveryWith is like Recovery but logs through l. A nil l uses the global
+// logger.
+func RecoveryWith(l logger.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) (err error) {
+		start := time.Now()
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+			r := request(c, start)
+			r.Status = fiber.StatusInternalServerError
+			httplog.LogPanic(httplog.LoggerOrGlobal(l), r, recovered)
+			err = fiber.ErrInternalServerError
+		}()
+		return c.Next()
+	}
+}
+
+// request describes the request of c. Fiber reuses the memory of its
+// strings once the handler returns, so they are copied.
+func request(c *fiber.Ctx, start time.Time) httplog.Request {
+	requestID := c.Get(httplog.RequestIDHeader)
+	if requestID == "" {
+		requestID = c.GetRespHeader(httplog.RequestIDHeader)
+	}
+	return httplog.Request{
+		Start:         start,
+		Method:        strings.Clone(c.Method()),
+		Path:          strings.Clone(c.Path()),
+		URI:           string(c.Request().RequestURI()),
+		Proto:         string(c.Request().Header.Protocol()),
+		Status:        c.Response().StatusCode(),
+		Bytes:         int64(len(c.Response().Body())),
+		Latency:       time.Since(start),
+		ClientIP:      strings.Clone(c.IP()),
+		RequestID:     strings.Clone(requestID),
+		Referer:       strings.Clone(c.Get(fiber.HeaderReferer)),
+		UserAgent:     strings.Clone(c.Get(fiber.HeaderUserAgent)),
+		Authorization: strings.Clone(c.Get(fiber.HeaderAuthorization)),
+	}
+}
@@ -1,9 +1,79 @@
 package logger
 
 import (
+	"fmt"
+	"sort"
+	"time"
+
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
+// Fields converts a map into fields ordered by key. Values are converted
+// with Any, so structs honor their log tags.
+func Fields(m map[string]any) []zap.Field {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([]zap.Field, 0, len(keys))
+	for _, key := range keys {
+		fields = append(fields, Any(key, m[key]))
+	}
+	return fields
+}
+
+// KeysAndValues converts alternating keys and values, as taken by the
+// sugared loggers of other libraries, into fields. Errors become error
+// fields, other values are converted with Any, and a key without a value
+// gets a nil value.
+func KeysAndValues(keyvals ...any) []zap.Field {
+	fields := make([]zap.Field, 0, (len(keyvals)+1)/2)
+	for i := 0; i < len(keyvals); i += 2 {
+		key, ok := keyvals[i].(string)
+		if !ok {
+			key = fmt.Sprint(keyvals[i])
+		}
+		var value any
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+		if err, ok := value.(error); ok {
+			fields = append(fields, zap.NamedError(key, err))
+			continue
+		}
+		fields = append(fields, Any(key, value))
+	}
+	return fields
+}
+
+// fieldKey returns the key a field is written under, looking through the
+// inline fields created by Lazy
+func fieldKey(f zap.Field) string {
+	if f.Type == zapcore.InlineMarshalerType {
+		if v, ok := f.Interface.(lazyField); ok {
+			return v.key
+		}
+	}
+	return f.Key
+}
+
+// fieldValue returns the value of the last field with the given key among
+// fields, formatted as a string
+func fieldValue(fields []zapcore.Field, key string) (string, bool) {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fieldKey(fields[i]) != key {
+			continue
+		}
+		enc := zapcore.NewMapObjectEncoder()
+		fields[i].AddTo(enc)
+		return fmt.Sprint(enc.Fields[key]), true
+	}
+	return "", false
+}
+
 // Common field helpers
 
 // String creates a string field
@@ -21,6 +91,21 @@ func Int64(key string, val int64) zap.Field {
 	return zap.Int64(key, val)
 }
 
+// Int32 creates an int32 field
+func Int32(key string, val int32) zap.Field {
+	return zap.Int32(key, val)
+}
+
+// Int16 creates an int16 field
+func Int16(key string, val int16) zap.Field {
+	return zap.Int16(key, val)
+}
+
+// Int8 creates an int8 field
+func Int8(key string, val int8) zap.Field {
+	return zap.Int8(key, val)
+}
+
 // Uint creates a uint field
 func Uint(key string, val uint) zap.Field {
 	return zap.Uint(key, val)
@@ -36,27 +121,173 @@ func Uint64(key string, val uint64) zap.Field {
 	return zap.Uint64(key, val)
 }
 
+// Uint16 creates a uint16 field
+func Uint16(key string, val uint16) zap.Field {
+	return zap.Uint16(key, val)
+}
+
+// Uint8 creates a uint8 field
+func Uint8(key string, val uint8) zap.Field {
+	return zap.Uint8(key, val)
+}
+
 // Float64 creates a float64 field
 func Float64(key string, val float64) zap.Field {
 	return zap.Float64(key, val)
 }
 
+// Float32 creates a float32 field
+func Float32(key string, val float32) zap.Field {
+	return zap.Float32(key, val)
+}
+
+// Complex128 creates a complex128 field
+func Complex128(key string, val complex128) zap.Field {
+	return zap.Complex128(key, val)
+}
+
+// Complex64 creates a complex64 field
+func Complex64(key string, val complex64) zap.Field {
+	return zap.Complex64(key, val)
+}
+
 // Bool creates a bool field
 func Bool(key string, val bool) zap.Field {
 	return zap.Bool(key, val)
 }
 
-// Any creates a field with any value
+// ByteString creates a field for UTF-8 encoded text stored as bytes
+func ByteString(key string, val []byte) zap.Field {
+	return zap.ByteString(key, val)
+}
+
+// Binary creates a field for opaque binary data, base64-encoded by JSON encoders
+func Binary(key string, val []byte) zap.Field {
+	return zap.Binary(key, val)
+}
+
+// Time creates a time field encoded with the configured time encoding
+func Time(key string, val time.Time) zap.Field {
+	return zap.Time(key, val)
+}
+
+// Stringer creates a field from the value's String method, called lazily
+func Stringer(key string, val fmt.Stringer) zap.Field {
+	return zap.Stringer(key, val)
+}
+
+// Strings creates a string slice field
+func Strings(key string, vals []string) zap.Field {
+	return zap.Strings(key, vals)
+}
+
+// Ints creates an int slice field
+func Ints(key string, vals []int) zap.Field {
+	return zap.Ints(key, vals)
+}
+
+// Int64s creates an int64 slice field
+func Int64s(key string, vals []int64) zap.Field {
+	return zap.Int64s(key, vals)
+}
+
+// Float64s creates a float64 slice field
+func Float64s(key string, vals []float64) zap.Field {
+	return zap.Float64s(key, vals)
+}
+
+// Bools creates a bool slice field
+func Bools(key string, vals []bool) zap.Field {
+	return zap.Bools(key, vals)
+}
+
+// Times creates a time slice field
+func Times(key string, vals []time.Time) zap.Field {
+	return zap.Times(key, vals)
+}
+
+// Object creates a field from a value implementing zapcore.ObjectMarshaler
+func Object(key string, val zapcore.ObjectMarshaler) zap.Field {
+	return zap.Object(key, val)
+}
+
+// Array creates a field from a value implementing zapcore.ArrayMarshaler
+func Array(key string, val zapcore.ArrayMarshaler) zap.Field {
+	return zap.Array(key, val)
+}
+
+// Dict creates a nested object from fields, e.g.
+// Dict("request", String("method", "GET"), Int("status", 200))
+func Dict(key string, fields ...zap.Field) zap.Field {
+	return zap.Object(key, fieldObject(fields))
+}
+
+// fieldObject encodes a list of fields as an object
+type fieldObject []zap.Field
+
+func (o fieldObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, f := range o {
+		f.AddTo(enc)
+	}
+	return nil
+}
+
+// Lazy creates a field whose value is computed only when the entry is
+// encoded, so expensive diagnostics cost nothing when the level is disabled.
+// Fields passed to With are encoded once, when the child logger is created.
+func Lazy(key string, fn func() any) zap.Field {
+	return zap.Inline(lazyField{key: key, fn: fn})
+}
+
+// lazyField defers computing a field value until it is encoded
+type lazyField struct {
+	key string
+	fn  func() any
+}
+
+func (f lazyField) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	zap.Any(f.key, f.fn()).AddTo(enc)
+	return nil
+}
+
+// Namespace opens a nested namespace; all fields added after it, on the
+// entry or on child loggers, are placed inside the namespace
+func Namespace(key string) zap.Field {
+	return zap.Namespace(key)
+}
+
+// Skip creates a no-op field, useful when a field is conditionally added
+func Skip() zap.Field {
+	return zap.Skip()
+}
+
+// Any creates a field with any value. Structs honor `log:"-"` (omit) and
+// `log:"mask"` (mask the value) tags on their fields.
 func Any(key string, val any) zap.Field {
+	if _, ok := val.(zapcore.ObjectMarshaler); !ok {
+		if f, ok := taggedStructField(key, val); ok {
+			return f
+		}
+	}
 	return zap.Any(key, val)
 }
 
-// Error creates an error field
-func Err(err error) zap.Field {
-	return zap.Error(err)
+// Duration creates a duration field encoded with the encoder's duration format
+func Duration(key string, val time.Duration) zap.Field {
+	return zap.Duration(key, val)
 }
 
-// Duration creates a duration field
-func Duration(key string, val any) zap.Field {
-	return zap.Any(key, val)
+// DurationMs creates a field with the duration in fractional milliseconds
+func DurationMs(key string, val time.Duration) zap.Field {
+	return zap.Float64(key, float64(val)/float64(time.Millisecond))
+}
+
+// Since creates a duration field with the time elapsed since start
+func Since(key string, start time.Time) zap.Field {
+	return zap.Duration(key, time.Since(start))
+}
+
+// Latency creates a "latency" duration field with the time elapsed since start
+func Latency(start time.Time) zap.Field {
+	return Since("latency", start)
 }
@@ -0,0 +1,50 @@
+package logger
+
+import "errors"
+
+// File is a log file opened by OpenFile, for writing records of another
+// format, such as access logs, with the rotation of the logger
+type File struct {
+	sink sink
+}
+
+// OpenFile opens a file with the rotation, retention, compression and
+// buffering options of a log file. Each Write should be a complete record.
+func OpenFile(options FileOptions) (*File, error) {
+	name := options.Name
+	if name == "" {
+		name = options.Filename
+	}
+	s, err := newFileSink(name, options, DefaultConfig(), false)
+	if err != nil {
+		return nil, err
+	}
+	return &File{sink: s}, nil
+}
+
+// Write writes a record to the file
+func (f *File) Write(p []byte) (int, error) {
+	return f.sink.writer.Write(p)
+}
+
+// Sync flushes buffered records to disk
+func (f *File) Sync() error {
+	return f.sink.writer.Sync()
+}
+
+// Rotate forces a rotation of the file
+func (f *File) Rotate() error {
+	if f.sink.rotate == nil {
+		return errNoRotatingFile
+	}
+	return f.sink.rotate()
+}
+
+// Close flushes the file and releases its background resources
+func (f *File) Close() error {
+	err := f.Sync()
+	if f.sink.close != nil {
+		err = errors.Join(err, f.sink.close())
+	}
+	return err
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// lockedWriter serializes the writes of processes sharing a log file with
+// an advisory lock on a lock file next to it. Holding the lock, it reopens
+// the file if another process rotated or wrote to it since its last write,
+// so that the rotating writer sees the actual file and size.
+type lockedWriter struct {
+	zapcore.WriteSyncer
+	lock     *os.File
+	filename func() string
+	reopen   func() error
+
+	mu   sync.Mutex
+	last os.FileInfo
+}
+
+func newLockedWriter(w zapcore.WriteSyncer, file io.Writer, options FileOptions) (*lockedWriter, error) {
+	lock, err := os.OpenFile(linkFilename(options.Filename)+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(lock); err != nil {
+		lock.Close()
+		return nil, err
+	}
+	unlockFile(lock)
+
+	return &lockedWriter{
+		WriteSyncer: w,
+		lock:        lock,
+		filename:    activeFilename(file, options),
+		reopen:      reopenFunc(file),
+	}, nil
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := lockFile(w.lock); err != nil {
+		return 0, err
+	}
+	defer unlockFile(w.lock)
+
+	if w.last != nil && w.reopen != nil {
+		info, err := os.Stat(w.filename())
+		if err != nil || !os.SameFile(info, w.last) || info.Size() != w.last.Size() {
+			w.reopen()
+		}
+	}
+	n, err := w.WriteSyncer.Write(p)
+	w.last, _ = os.Stat(w.filename())
+	return n, err
+}
+
+// Close closes the lock file
+func (w *lockedWriter) Close() error {
+	return w.lock.Close()
+}
@@ -0,0 +1,19 @@
+//go:build !unix
+
+package logger
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// lockFile takes an exclusive advisory lock on f, waiting for it
+func lockFile(*os.File) error {
+	return fmt.Errorf("logger: file locking is not supported on %s", runtime.GOOS)
+}
+
+// unlockFile releases the lock on f
+func unlockFile(*os.File) error {
+	return nil
+}
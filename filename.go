@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Placeholders of FileOptions.Filename
+const (
+	hostnamePlaceholder = "{hostname}"
+	pidPlaceholder      = "{pid}"
+	timePlaceholder     = "{time}"
+	tenantPlaceholder   = "{tenant}" // TenantOptions.File only
+)
+
+// expandFilename replaces the {hostname} and {pid} placeholders of a
+// filename. {time} is left to the rotation.
+func expandFilename(filename string) string {
+	if strings.Contains(filename, hostnamePlaceholder) {
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = "unknown"
+		}
+		filename = strings.ReplaceAll(filename, hostnamePlaceholder, hostname)
+	}
+	return strings.ReplaceAll(filename, pidPlaceholder, strconv.Itoa(os.Getpid()))
+}
+
+// startFilename returns the file written to by a writer without time-based
+// rotation, replacing {time} with the current time
+func startFilename(options FileOptions) string {
+	if !strings.Contains(options.Filename, timePlaceholder) {
+		return options.Filename
+	}
+	format := options.TimeRotationFormat
+	if format == "" {
+		format = "2006-01-02T15-04-05"
+	}
+	now := time.Now().UTC()
+	if options.LocalTime {
+		now = now.Local()
+	}
+	return strings.ReplaceAll(options.Filename, timePlaceholder, now.Format(format))
+}
+
+// linkFilename returns the stable name of a filename template, without the
+// {time} placeholder and a separator next to it
+func linkFilename(filename string) string {
+	for _, sep := range []string{"-", "_", "."} {
+		filename = strings.Replace(filename, sep+timePlaceholder, "", 1)
+		filename = strings.Replace(filename, timePlaceholder+sep, "", 1)
+	}
+	return strings.Replace(filename, timePlaceholder, "", 1)
+}
@@ -0,0 +1,67 @@
+// Package gcsarchive uploads rotated log files to Google Cloud Storage.
+//
+//	client, err := storage.NewClient(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	uploader := gcsarchive.New(client, "my-log-bucket")
+//	config := logger.ProductionConfigWithFile("logs/app.log").
+//		WithArchive(uploader, "app/{host}/{date}", true)
+package gcsarchive
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"strings"
+
+	"cloud.google.com/go/storage"
+)
+
+// Uploader implements logger.Uploader for a GCS bucket
+type Uploader struct {
+	client *storage.Client
+	bucket string
+}
+
+// New creates an Uploader storing files in bucket
+func New(client *storage.Client, bucket string) *Uploader {
+	return &Uploader{client: client, bucket: bucket}
+}
+
+// Upload writes the file to the object named key. GCS rejects the write
+// unless the stored object has the CRC32C checksum of the file.
+func (u *Uploader) Upload(ctx context.Context, key, localPath string) error {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	checksum := crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	if _, err := io.Copy(checksum, f); err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	w := u.client.Bucket(u.bucket).Object(key).NewWriter(ctx)
+	w.ContentType = "text/plain"
+	if strings.HasSuffix(localPath, ".gz") {
+		w.ContentType = "application/gzip"
+	}
+	w.CRC32C = checksum.Sum32()
+	w.SendCRC32C = true
+
+	if _, err := io.Copy(w, f); err != nil {
+		w.Close()
+		return fmt.Errorf("gcsarchive: write gs://%s/%s: %w", u.bucket, key, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("gcsarchive: write gs://%s/%s: %w", u.bucket, key, err)
+	}
+	return nil
+}
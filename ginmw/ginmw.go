@@ -0,0 +1,119 @@
+// Package ginmw provides Gin middleware logging requests and recovered panics
+// through the logger package.
+//
+//	router := gin.New()
+//	router.Use(ginmw.Logger(), ginmw.Recovery())
+//	ginmw.ReplaceWriters()
+package ginmw
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/csmart-libs/go-logger"
+	"github.com/csmart-libs/go-logger/accesslog"
+	"github.com/csmart-libs/go-logger/internal/httplog"
+	"github.com/gin-gonic/gin"
+)
+
+// Logger returns middleware logging each request with its method, path,
+// status, latency, client IP and request ID through the global logger
+func Logger() gin.HandlerFunc {
+	return LoggerWith(nil)
+}
+
+// LoggerWith is like Logger but logs through l. A nil l uses the global
+// logger.
+func LoggerWith(l logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		httplog.Log(httplog.LoggerOrGlobal(l), request(c, start))
+	}
+}
+
+// AccessLog returns middleware writing an access log line for each request
+// to access, e.g. in the Apache combined format for legacy analyzers. It
+// runs alongside Logger.
+func AccessLog(access *accesslog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		_ = access.Log(request(c, start).AccessEntry())
+	}
+}
+
+// Recovery returns middleware recovering from panics in later handlers,
+// logging them with their stack trace through the global logger and
+// responding with status 500
+func Recovery() gin.HandlerFunc {
+	return RecoveryWith(nil)
+}
+
+// RecoveryWith is like Recovery but logs through l. A nil l uses the global
+// logger.
+func RecoveryWith(l logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+			if !c.Writer.Written() {
+				c.AbortWithStatus(http.StatusInternalServerError)
+			} else {
+				c.Abort()
+			}
+			r := request(c, start)
+			r.Status = http.StatusInternalServerError
+			httplog.LogPanic(httplog.LoggerOrGlobal(l), r, recovered)
+		}()
+		c.Next()
+	}
+}
+
+// ReplaceWriters redirects gin's default writers, used for its debug and
+// error messages, to the global logger at debug and error level
+func ReplaceWriters() {
+	gin.DefaultWriter = lineWriter(func(line string) { logger.GetLogger().Named("gin").Debug(line) })
+	gin.DefaultErrorWriter = lineWriter(func(line string) { logger.GetLogger().Named("gin").Error(line) })
+}
+
+// lineWriter logs each non-empty line written to it
+type lineWriter func(line string)
+
+func (w lineWriter) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			w(string(line))
+		}
+	}
+	return len(p), nil
+}
+
+func request(c *gin.Context, start time.Time) httplog.Request {
+	requestID := c.GetHeader(httplog.RequestIDHeader)
+	if requestID == "" {
+		requestID = c.Writer.Header().Get(httplog.RequestIDHeader)
+	}
+	return httplog.Request{
+		Start:         start,
+		Method:        c.Request.Method,
+		Path:          c.Request.URL.Path,
+		URI:           c.Request.RequestURI,
+		Proto:         c.Request.Proto,
+		Status:        c.Writer.Status(),
+		Bytes:         int64(max(c.Writer.Size(), 0)),
+		Latency:       time.Since(start),
+		ClientIP:      c.ClientIP(),
+		RequestID:     requestID,
+		Referer:       c.Request.Referer(),
+		UserAgent:     c.Request.UserAgent(),
+		Authorization: c.GetHeader("Authorization"),
+	}
+}
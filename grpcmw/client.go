@@ -0,0 +1,153 @@
+package grpcmw
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/csmart-libs/go-logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// previousAttemptsKey is the header in which gRPC reports the number of
+// retries of a call
+const previousAttemptsKey = "grpc-previous-rpc-attempts"
+
+// UnaryClientInterceptor returns an interceptor logging each outbound unary
+// call with its method, code, latency, peer, request ID and number of
+// retries. The request ID of the incoming call being served, if any, is
+// propagated to the outgoing metadata.
+func UnaryClientInterceptor(options Options) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		ctx, id := propagateRequestID(ctx)
+		var header metadata.MD
+		var p peer.Peer
+		opts = append(opts, grpc.Header(&header), grpc.Peer(&p))
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		fields := clientFields(method, err, start, &p, id, header)
+		if options.LogPayloads {
+			fields = append(fields, payload("request", req))
+			if err == nil {
+				fields = append(fields, payload("response", reply))
+			}
+		}
+		options.logger().Log(options.level(method, status.Code(err)), "grpc client call", fields...)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns an interceptor logging each outbound
+// streaming call when it ends, like UnaryClientInterceptor
+func StreamClientInterceptor(options Options) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		ctx, id := propagateRequestID(ctx)
+		p := &peer.Peer{}
+		opts = append(opts, grpc.Peer(p))
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			options.logger().Log(options.level(method, status.Code(err)), "grpc client stream",
+				clientFields(method, err, start, p, id, nil)...)
+			return nil, err
+		}
+		return &loggedClientStream{
+			ClientStream:  stream,
+			options:       options,
+			method:        method,
+			requestID:     id,
+			start:         start,
+			peer:          p,
+			serverStreams: desc.ServerStreams,
+		}, nil
+	}
+}
+
+// loggedClientStream logs a client stream once it ends
+type loggedClientStream struct {
+	grpc.ClientStream
+	options   Options
+	method    string
+	requestID string
+	start     time.Time
+	peer      *peer.Peer
+
+	// serverStreams is false when the server sends a single response
+	serverStreams bool
+	done          bool
+}
+
+func (s *loggedClientStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil && s.options.LogPayloads {
+		s.options.logger().Debug("grpc message sent", logger.String("method", s.method), payload("request", m))
+	}
+	return err
+}
+
+func (s *loggedClientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.finish(err)
+		return err
+	}
+	if s.options.LogPayloads {
+		s.options.logger().Debug("grpc message received", logger.String("method", s.method), payload("response", m))
+	}
+	if !s.serverStreams {
+		// The single response of a client streaming call ends it
+		s.finish(nil)
+	}
+	return nil
+}
+
+// finish logs the end of the stream; io.EOF is a successful end
+func (s *loggedClientStream) finish(err error) {
+	if s.done {
+		return
+	}
+	s.done = true
+	if err == io.EOF {
+		err = nil
+	}
+	header, _ := s.ClientStream.Header()
+	fields := clientFields(s.method, err, s.start, s.peer, s.requestID, header)
+	s.options.logger().Log(s.options.level(s.method, status.Code(err)), "grpc client stream", fields...)
+}
+
+// clientFields returns the fields of a finished outbound call
+func clientFields(method string, err error, start time.Time, p *peer.Peer, requestID string, header metadata.MD) []zap.Field {
+	var addr string
+	if p != nil && p.Addr != nil {
+		addr = p.Addr.String()
+	}
+	fields := callFields(method, err, start, addr, requestID)
+	if values := header.Get(previousAttemptsKey); len(values) > 0 {
+		if retries, err := strconv.Atoi(values[0]); err == nil && retries > 0 {
+			fields = append(fields, logger.Int("retries", retries))
+		}
+	}
+	return fields
+}
+
+// propagateRequestID returns ctx with the request ID of the call being
+// served added to the outgoing metadata, unless it already has one, and the
+// request ID of the outbound call
+func propagateRequestID(ctx context.Context) (context.Context, string) {
+	outgoing, _ := metadata.FromOutgoingContext(ctx)
+	if id := requestID(outgoing); id != "" {
+		return ctx, id
+	}
+	incoming, _ := metadata.FromIncomingContext(ctx)
+	id := requestID(incoming)
+	if id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, RequestIDKey, id)
+	}
+	return ctx, id
+}
@@ -0,0 +1,26 @@
+module github.com/csmart-libs/go-logger/grpcmw
+
+go 1.24.4
+
+require (
+	github.com/csmart-libs/go-logger v0.0.0-00010101000000-000000000000
+	go.uber.org/zap v1.27.0
+	google.golang.org/grpc v1.67.3
+	google.golang.org/protobuf v1.36.5
+)
+
+require (
+	filippo.io/age v1.2.1 // indirect
+	github.com/klauspost/compress v1.18.0 // indirect
+	go.opentelemetry.io/otel v1.36.0 // indirect
+	go.opentelemetry.io/otel/trace v1.36.0 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	golang.org/x/crypto v0.37.0 // indirect
+	golang.org/x/net v0.39.0 // indirect
+	golang.org/x/sys v0.32.0 // indirect
+	golang.org/x/text v0.24.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20241209162323-e6fa225c2576 // indirect
+	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
+)
+
+replace github.com/csmart-libs/go-logger => ../
@@ -0,0 +1,117 @@
+// Package grpcmw provides gRPC interceptors logging RPCs through the logger
+// package.
+//
+//	server := grpc.NewServer(
+//		grpc.ChainUnaryInterceptor(grpcmw.UnaryServerInterceptor(grpcmw.Options{})),
+//		grpc.ChainStreamInterceptor(grpcmw.StreamServerInterceptor(grpcmw.Options{})),
+//	)
+//	conn, err := grpc.NewClient(target,
+//		grpc.WithChainUnaryInterceptor(grpcmw.UnaryClientInterceptor(grpcmw.Options{})),
+//	)
+package grpcmw
+
+import (
+	"context"
+	"time"
+
+	"github.com/csmart-libs/go-logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// RequestIDKey is the metadata key carrying the request ID
+const RequestIDKey = "x-request-id"
+
+// Options configures the interceptors
+type Options struct {
+	// Logger receives the RPC entries. Nil uses the global logger.
+	Logger logger.Logger
+
+	// MethodLevels sets the level of successful calls per full method name,
+	// e.g. "debug" for "/grpc.health.v1.Health/Check". Failed calls keep the
+	// level of their code.
+	MethodLevels map[string]string
+
+	// LogPayloads adds the request and response messages to the entries.
+	// Stream messages are logged at debug level.
+	LogPayloads bool
+}
+
+func (o Options) logger() logger.Logger {
+	if o.Logger == nil {
+		return logger.GetLogger()
+	}
+	return o.Logger
+}
+
+// level returns the level of a call's entry: info for OK, warn for errors
+// caused by the client and error for server failures, unless MethodLevels
+// overrides a successful call
+func (o Options) level(method string, code codes.Code) string {
+	switch code {
+	case codes.OK:
+		if level, ok := o.MethodLevels[method]; ok {
+			return level
+		}
+		return "info"
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.Unauthenticated, codes.ResourceExhausted,
+		codes.FailedPrecondition, codes.Aborted, codes.OutOfRange:
+		return "warn"
+	default:
+		return "error"
+	}
+}
+
+// callFields returns the fields of a finished call
+func callFields(method string, err error, start time.Time, peerAddr, requestID string) []zap.Field {
+	fields := []zap.Field{
+		logger.String("method", method),
+		logger.String("code", status.Code(err).String()),
+		logger.Latency(start),
+	}
+	if peerAddr != "" {
+		fields = append(fields, logger.String("peer", peerAddr))
+	}
+	if requestID != "" {
+		fields = append(fields, logger.String("request_id", requestID))
+	}
+	if err != nil {
+		fields = append(fields, logger.Err(err))
+	}
+	return fields
+}
+
+// peerAddr returns the address of the peer of ctx
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// requestID returns the first request ID of md
+func requestID(md metadata.MD) string {
+	if values := md.Get(RequestIDKey); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// payload creates a field with a message, rendered as JSON when it is a
+// protobuf message
+func payload(key string, msg any) zap.Field {
+	return logger.Lazy(key, func() any {
+		if m, ok := msg.(proto.Message); ok {
+			if b, err := protojson.Marshal(m); err == nil {
+				return string(b)
+			}
+		}
+		return msg
+	})
+}
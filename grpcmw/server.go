@@ -0,0 +1,84 @@
+package grpcmw
+
+import (
+	"context"
+	"time"
+
+	"github.com/csmart-libs/go-logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor returns an interceptor logging each unary call
+// with its method, code, latency, peer and the request ID of its metadata
+func UnaryServerInterceptor(options Options) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		fields := callFields(info.FullMethod, err, start, peerAddr(ctx), requestID(md))
+		if options.LogPayloads {
+			fields = append(fields, payload("request", req))
+			if err == nil {
+				fields = append(fields, payload("response", resp))
+			}
+		}
+		options.logger().Log(options.level(info.FullMethod, status.Code(err)), "grpc call", fields...)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns an interceptor logging each streaming call
+// when it ends, like UnaryServerInterceptor
+func StreamServerInterceptor(options Options) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := stream.Context()
+		md, _ := metadata.FromIncomingContext(ctx)
+		id := requestID(md)
+
+		if options.LogPayloads {
+			stream = &payloadServerStream{ServerStream: stream, options: options, method: info.FullMethod, requestID: id}
+		}
+		err := handler(srv, stream)
+
+		fields := callFields(info.FullMethod, err, start, peerAddr(ctx), id)
+		options.logger().Log(options.level(info.FullMethod, status.Code(err)), "grpc stream", fields...)
+		return err
+	}
+}
+
+// payloadServerStream logs the messages of a stream
+type payloadServerStream struct {
+	grpc.ServerStream
+	options   Options
+	method    string
+	requestID string
+}
+
+func (s *payloadServerStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.log("grpc message sent", payload("response", m))
+	}
+	return err
+}
+
+func (s *payloadServerStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.log("grpc message received", payload("request", m))
+	}
+	return err
+}
+
+func (s *payloadServerStream) log(msg string, field zap.Field) {
+	fields := []zap.Field{logger.String("method", s.method)}
+	if s.requestID != "" {
+		fields = append(fields, logger.String("request_id", s.requestID))
+	}
+	s.options.logger().Debug(msg, append(fields, field)...)
+}
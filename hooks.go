@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Entry is a log entry as seen by hooks. Fields holds the fields passed to
+// the logging call; fields added through With are not included.
+type Entry struct {
+	Level   string
+	Logger  string
+	Message string
+	Time    time.Time
+	Fields  []zap.Field
+}
+
+// Hook inspects an entry before it is encoded. It may change the entry,
+// e.g. add mandatory fields or normalize tags, and returns false to drop it.
+// Hooks run in order after the level check and before redaction.
+type Hook func(entry *Entry) bool
+
+// newHookCore runs hooks on every entry written through a core
+func newHookCore(core zapcore.Core, hooks []Hook) zapcore.Core {
+	return newTransformCore(core, &entryTransform{
+		write: func(ent *zapcore.Entry, fields []zapcore.Field) ([]zapcore.Field, bool) {
+			entry := Entry{
+				Level:   levelName(ent.Level),
+				Logger:  ent.LoggerName,
+				Message: ent.Message,
+				Time:    ent.Time,
+				Fields:  fields,
+			}
+			for _, hook := range hooks {
+				if !hook(&entry) {
+					return nil, false
+				}
+			}
+			if entry.Level != levelName(ent.Level) {
+				if level, err := ParseLevel(entry.Level); err == nil {
+					ent.Level = level
+				}
+			}
+			ent.LoggerName = entry.Logger
+			ent.Message = entry.Message
+			ent.Time = entry.Time
+			return entry.Fields, true
+		},
+	})
+}
@@ -0,0 +1,83 @@
+// Package httpclientlog adapts the logger package to the logger interfaces
+// of HTTP client libraries, so that their retries and errors are logged
+// through it instead of the standard library logger.
+//
+//	// hashicorp/go-retryablehttp
+//	client := retryablehttp.NewClient()
+//	client.Logger = httpclientlog.NewLeveledLogger(nil)
+//
+//	// go-resty/resty
+//	client := resty.New().SetLogger(httpclientlog.NewRestyLogger(nil))
+package httpclientlog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/csmart-libs/go-logger"
+)
+
+// clientLogger returns l, or the global logger named "http.client" if l is
+// nil, skipping the adapter methods so that entries carry the caller in
+// the HTTP client
+func clientLogger(l logger.Logger) logger.Logger {
+	if l == nil {
+		l = logger.Named("http.client")
+	}
+	return logger.ApplyOptions(l, logger.AddCallerSkip(1))
+}
+
+// LeveledLogger implements retryablehttp.LeveledLogger
+type LeveledLogger struct {
+	logger logger.Logger
+}
+
+// NewLeveledLogger creates a logger for retryablehttp.Client.Logger logging
+// through l. A nil l uses the global logger named "http.client".
+func NewLeveledLogger(l logger.Logger) *LeveledLogger {
+	return &LeveledLogger{logger: clientLogger(l)}
+}
+
+func (r *LeveledLogger) Error(msg string, keysAndValues ...any) {
+	r.logger.Error(msg, logger.KeysAndValues(keysAndValues...)...)
+}
+
+func (r *LeveledLogger) Warn(msg string, keysAndValues ...any) {
+	r.logger.Warn(msg, logger.KeysAndValues(keysAndValues...)...)
+}
+
+func (r *LeveledLogger) Info(msg string, keysAndValues ...any) {
+	r.logger.Info(msg, logger.KeysAndValues(keysAndValues...)...)
+}
+
+func (r *LeveledLogger) Debug(msg string, keysAndValues ...any) {
+	r.logger.Debug(msg, logger.KeysAndValues(keysAndValues...)...)
+}
+
+// RestyLogger implements resty.Logger
+type RestyLogger struct {
+	logger logger.Logger
+}
+
+// NewRestyLogger creates a logger for resty.Client.SetLogger logging
+// through l. A nil l uses the global logger named "http.client".
+func NewRestyLogger(l logger.Logger) *RestyLogger {
+	return &RestyLogger{logger: clientLogger(l)}
+}
+
+func (r *RestyLogger) Errorf(format string, v ...any) {
+	r.logger.Error(message(format, v))
+}
+
+func (r *RestyLogger) Warnf(format string, v ...any) {
+	r.logger.Warn(message(format, v))
+}
+
+func (r *RestyLogger) Debugf(format string, v ...any) {
+	r.logger.Debug(message(format, v))
+}
+
+// message formats a printf-style message without its trailing newline
+func message(format string, v []any) string {
+	return strings.TrimSpace(fmt.Sprintf(format, v...))
+}
@@ -0,0 +1,200 @@
+// Package httpmw provides net/http middleware logging requests and recovered
+// panics through the logger package, with the same fields as ginmw. It works
+// with the standard ServeMux and with routers such as chi and gorilla/mux.
+//
+//	mux := http.NewServeMux()
+//	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+//		httpmw.FromContext(r.Context()).Info("handling")
+//	})
+//	http.ListenAndServe(":8080", httpmw.Handler(mux))
+package httpmw
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/csmart-libs/go-logger"
+	"github.com/csmart-libs/go-logger/accesslog"
+	"github.com/csmart-libs/go-logger/internal/httplog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Handler logs each request handled by next with its method, path, status,
+// response bytes, latency, client IP and request ID through the global
+// logger, and recovers from panics in next, responding with status 500
+func Handler(next http.Handler) http.Handler {
+	return HandlerWith(nil)(next)
+}
+
+// HandlerWith returns middleware like Handler logging through l. A nil l
+// uses the global logger.
+func HandlerWith(l logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			base := httplog.LoggerOrGlobal(l)
+			requestID := r.Header.Get(httplog.RequestIDHeader)
+			reqLogger := base
+			if requestID != "" {
+				reqLogger = base.With(logger.String("request_id", requestID))
+			}
+			sw := &statusWriter{ResponseWriter: w}
+			ctx := logger.NewContext(r.Context(), reqLogger)
+			if traceparent := r.Header.Get(logger.TraceparentHeader); traceparent != "" && !trace.SpanContextFromContext(ctx).IsValid() {
+				// Keep the caller's trace without a tracing SDK
+				if traced, err := logger.ContextWithTraceparent(ctx, traceparent); err == nil {
+					ctx = traced
+				}
+			}
+			r = r.WithContext(ctx)
+
+			defer func() {
+				req := request(r, sw, start)
+				if recovered := recover(); recovered != nil {
+					if recovered == http.ErrAbortHandler {
+						panic(recovered)
+					}
+					if !sw.wroteHeader {
+						sw.WriteHeader(http.StatusInternalServerError)
+					}
+					req.Status = http.StatusInternalServerError
+					httplog.LogPanic(base, req, recovered)
+				}
+				httplog.Log(base, req)
+			}()
+			next.ServeHTTP(sw, r)
+		})
+	}
+}
+
+// AccessLog returns middleware writing an access log line for each request
+// to access, e.g. in the Apache combined format for legacy analyzers. It
+// runs alongside Handler; a panic is logged with status 500 and passed on.
+func AccessLog(access *accesslog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			sw := &statusWriter{ResponseWriter: w}
+			defer func() {
+				req := request(r, sw, start)
+				recovered := recover()
+				if recovered != nil {
+					req.Status = http.StatusInternalServerError
+				}
+				_ = access.Log(req.AccessEntry())
+				if recovered != nil {
+					panic(recovered)
+				}
+			}()
+			next.ServeHTTP(sw, r)
+		})
+	}
+}
+
+// request describes the request r answered through sw
+func request(r *http.Request, sw *statusWriter, start time.Time) httplog.Request {
+	return httplog.Request{
+		Start:         start,
+		Method:        r.Method,
+		Path:          r.URL.Path,
+		URI:           r.RequestURI,
+		Proto:         r.Proto,
+		Status:        sw.status(),
+		Bytes:         sw.bytes,
+		Latency:       time.Since(start),
+		ClientIP:      clientIP(r),
+		RequestID:     r.Header.Get(httplog.RequestIDHeader),
+		Referer:       r.Referer(),
+		UserAgent:     r.UserAgent(),
+		Authorization: r.Header.Get("Authorization"),
+	}
+}
+
+// FromContext returns the request-scoped logger stored by Handler, which
+// carries the request ID, or the global logger outside of a request. It is
+// the same as logger.FromContext.
+func FromContext(ctx context.Context) logger.Logger {
+	return logger.FromContext(ctx)
+}
+
+// FromRequest returns the request-scoped logger of r
+func FromRequest(r *http.Request) logger.Logger {
+	return FromContext(r.Context())
+}
+
+// statusWriter records the status and size of a response
+type statusWriter struct {
+	http.ResponseWriter
+	code        int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.code = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush flushes the response if the underlying writer supports it
+func (w *statusWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		if !w.wroteHeader {
+			w.WriteHeader(http.StatusOK)
+		}
+		flusher.Flush()
+	}
+}
+
+// Hijack takes over the connection if the underlying writer supports it,
+// for WebSocket and other protocol upgrades that assert http.Hijacker. A
+// hijacked request is logged with status 101.
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("httpmw: hijack %T: %w", w.ResponseWriter, http.ErrNotSupported)
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err == nil && !w.wroteHeader {
+		w.code = http.StatusSwitchingProtocols
+		w.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the underlying writer for http.ResponseController
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// status returns the response status, 200 if the handler wrote nothing
+func (w *statusWriter) status() int {
+	if !w.wroteHeader {
+		return http.StatusOK
+	}
+	return w.code
+}
+
+// clientIP returns the host of the request's remote address
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,119 @@
+// Package httplog holds the request log fields and levels shared by the HTTP
+// middleware packages, so that every framework logs requests the same way.
+package httplog
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/csmart-libs/go-logger"
+	"github.com/csmart-libs/go-logger/accesslog"
+	"go.uber.org/zap"
+)
+
+// RequestIDHeader is the header carrying the request ID
+const RequestIDHeader = "X-Request-ID"
+
+// LoggerOrGlobal returns l, or the global logger if l is nil, so that
+// middleware created before Initialize logs through the final logger
+func LoggerOrGlobal(l logger.Logger) logger.Logger {
+	if l == nil {
+		return logger.GetLogger()
+	}
+	return l
+}
+
+// Request describes a handled request
+type Request struct {
+	Start     time.Time
+	Method    string
+	Path      string
+	URI       string
+	Proto     string
+	Status    int
+	Bytes     int64
+	Latency   time.Duration
+	ClientIP  string
+	RequestID string
+	Referer   string
+	UserAgent string
+
+	// Authorization is the Authorization header, giving the user of the
+	// access log
+	Authorization string
+}
+
+// Fields returns the fields logged for a request
+func (r Request) Fields() []zap.Field {
+	fields := []zap.Field{
+		logger.String("method", r.Method),
+		logger.String("path", r.Path),
+		logger.Int("status", r.Status),
+		logger.Int64("bytes", r.Bytes),
+		logger.Duration("latency", r.Latency),
+		logger.String("client_ip", r.ClientIP),
+	}
+	if r.RequestID != "" {
+		fields = append(fields, logger.String("request_id", r.RequestID))
+	}
+	return fields
+}
+
+// AccessEntry returns the access log entry of the request
+func (r Request) AccessEntry() accesslog.Entry {
+	return accesslog.Entry{
+		Time:      r.Start,
+		ClientIP:  r.ClientIP,
+		User:      basicAuthUser(r.Authorization),
+		Method:    r.Method,
+		URI:       r.URI,
+		Proto:     r.Proto,
+		Status:    r.Status,
+		Bytes:     r.Bytes,
+		Referer:   r.Referer,
+		UserAgent: r.UserAgent,
+		Latency:   r.Latency,
+		RequestID: r.RequestID,
+	}
+}
+
+// basicAuthUser returns the user name of a basic Authorization header
+func basicAuthUser(authorization string) string {
+	scheme, credentials, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Basic") {
+		return ""
+	}
+	decoded, err := base64.StdEncoding.DecodeString(credentials)
+	if err != nil {
+		return ""
+	}
+	user, _, _ := strings.Cut(string(decoded), ":")
+	return user
+}
+
+// Log writes the request log entry: errors for 5xx responses, warnings for
+// 4xx and info otherwise
+func Log(l logger.Logger, r Request) {
+	switch {
+	case r.Status >= http.StatusInternalServerError:
+		l.Error("request", r.Fields()...)
+	case r.Status >= http.StatusBadRequest:
+		l.Warn("request", r.Fields()...)
+	default:
+		l.Info("request", r.Fields()...)
+	}
+}
+
+// LogPanic writes the entry for a panic recovered while handling a request
+func LogPanic(l logger.Logger, r Request, recovered any) {
+	err, ok := recovered.(error)
+	if !ok {
+		err = fmt.Errorf("%v", recovered)
+	}
+	fields := append(r.Fields(), logger.Err(err), logger.String("stack", string(debug.Stack())))
+	l.Error("panic recovered", fields...)
+}
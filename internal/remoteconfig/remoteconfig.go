@@ -0,0 +1,153 @@
+// Package remoteconfig applies the logger settings stored in a key of a
+// configuration store, shared by the etcdconfig and consulconfig packages so
+// that both stores use the same document.
+package remoteconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/csmart-libs/go-logger"
+)
+
+// Settings is the JSON document stored in the watched key. Omitted
+// settings are left unchanged.
+//
+//	{
+//		"level": "debug",
+//		"levels": {"db": "warn"},
+//		"sampling": {"enabled": true, "initial": 100, "thereafter": 10, "tick": "1s"}
+//	}
+type Settings struct {
+	// Level is the root level
+	Level string `json:"level"`
+
+	// Levels are the named levels. Named levels set by a previous document
+	// and missing from this one are removed.
+	Levels map[string]string `json:"levels"`
+
+	// Sampling replaces the sampling options
+	Sampling *Sampling `json:"sampling"`
+}
+
+// Sampling is the JSON form of logger.SamplingOptions, with the tick as a
+// duration string
+type Sampling struct {
+	Enabled        bool                           `json:"enabled"`
+	Initial        int                            `json:"initial"`
+	Thereafter     int                            `json:"thereafter"`
+	Tick           string                         `json:"tick"`
+	Levels         map[string]logger.SamplingRate `json:"levels"`
+	UnsampledLevel string                         `json:"unsampled_level"`
+}
+
+// Applier applies settings documents to a logger
+type Applier struct {
+	logger *logger.ZapLogger
+
+	// named are the named levels set by the last document
+	named map[string]bool
+}
+
+// New creates an Applier for l, or for the global logger if l is nil
+func New(l logger.Logger) (*Applier, error) {
+	if l == nil {
+		l = logger.GetLogger()
+	}
+	zl, ok := l.(*logger.ZapLogger)
+	if !ok {
+		return nil, fmt.Errorf("logger: remote settings need a *ZapLogger, got %T", l)
+	}
+	return &Applier{logger: zl, named: map[string]bool{}}, nil
+}
+
+// Apply validates a settings document and applies it. An empty document,
+// e.g. of a deleted key, changes nothing.
+func (a *Applier) Apply(data []byte) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	var settings Settings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("logger: invalid remote settings: %w", err)
+	}
+
+	// Validate everything before changing anything
+	if settings.Level != "" {
+		if _, err := logger.ParseLevel(settings.Level); err != nil {
+			return fmt.Errorf("logger: invalid remote level: %w", err)
+		}
+	}
+	for name, level := range settings.Levels {
+		if _, err := logger.ParseLevel(level); err != nil {
+			return fmt.Errorf("logger: invalid remote level for %q: %w", name, err)
+		}
+	}
+	var sampling logger.SamplingOptions
+	if s := settings.Sampling; s != nil {
+		sampling = logger.SamplingOptions{
+			Enabled:        s.Enabled,
+			Initial:        s.Initial,
+			Thereafter:     s.Thereafter,
+			Levels:         s.Levels,
+			UnsampledLevel: s.UnsampledLevel,
+		}
+		// zap's sampler drops every entry with both rates at zero, which
+		// would silence the whole fleet
+		if s.Enabled && s.Initial <= 0 && s.Thereafter <= 0 {
+			return fmt.Errorf("logger: invalid remote sampling: initial and thereafter are both 0")
+		}
+		if s.Initial < 0 || s.Thereafter < 0 {
+			return fmt.Errorf("logger: invalid remote sampling: negative rate")
+		}
+		for level, rate := range s.Levels {
+			if rate.Initial <= 0 && rate.Thereafter <= 0 {
+				return fmt.Errorf("logger: invalid remote sampling for %q: initial and thereafter are both 0", level)
+			}
+		}
+		if s.Tick != "" {
+			tick, err := time.ParseDuration(s.Tick)
+			if err != nil {
+				return fmt.Errorf("logger: invalid remote sampling tick: %w", err)
+			}
+			sampling.Tick = tick
+		}
+	}
+
+	if settings.Level != "" {
+		a.logger.SetLevel(settings.Level)
+	}
+	for name, level := range settings.Levels {
+		a.logger.SetNamedLevel(name, level)
+	}
+	for name := range a.named {
+		if _, ok := settings.Levels[name]; !ok {
+			a.logger.UnsetNamedLevel(name)
+		}
+	}
+	a.named = make(map[string]bool, len(settings.Levels))
+	for name := range settings.Levels {
+		a.named[name] = true
+	}
+	if settings.Sampling != nil {
+		a.logger.SetSampling(sampling)
+	}
+	return nil
+}
+
+// Update applies a settings document read from source, logging the outcome
+// so that fleet-wide changes can be traced
+func (a *Applier) Update(source string, data []byte) {
+	if err := a.Apply(data); err != nil {
+		a.logger.Warn("remote logger settings rejected", logger.String("source", source), logger.Err(err))
+		return
+	}
+	a.logger.Info("remote logger settings applied", logger.String("source", source))
+}
+
+// Warn logs an error of the watcher
+func (a *Applier) Warn(msg string, err error) {
+	a.logger.Warn(msg, logger.Err(err))
+}
@@ -0,0 +1,121 @@
+// Package kafkalog adapts the logger package to the logger interfaces of
+// the Kafka clients, so that their connection, rebalance and produce
+// messages go through the configured levels and sinks instead of stdout.
+//
+//	// IBM/sarama
+//	sarama.Logger = kafkalog.NewSaramaLogger(nil)
+//
+//	// twmb/franz-go
+//	client, err := kgo.NewClient(kgo.WithLogger(kafkalog.NewKgoLogger(nil, kgo.LogLevelInfo)))
+//
+//	// segmentio/kafka-go
+//	writer := &kafka.Writer{
+//		Logger:      kafkalog.NewPrintfLogger(nil, "debug"),
+//		ErrorLogger: kafkalog.NewPrintfLogger(nil, "error"),
+//	}
+package kafkalog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/csmart-libs/go-logger"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// kafkaLogger returns l, or the global logger named "kafka" if l is nil,
+// skipping skip adapter frames so that entries carry the caller in the
+// Kafka client
+func kafkaLogger(l logger.Logger, skip int) logger.Logger {
+	if l == nil {
+		l = logger.Named("kafka")
+	}
+	return logger.ApplyOptions(l, logger.AddCallerSkip(skip))
+}
+
+// SaramaLogger implements sarama.StdLogger. Sarama logs everything
+// through one logger, so messages mentioning an error or failure are
+// logged at warn and the others at debug.
+type SaramaLogger struct {
+	logger logger.Logger
+}
+
+// NewSaramaLogger creates a logger for sarama.Logger logging through l. A
+// nil l uses the global logger named "kafka".
+func NewSaramaLogger(l logger.Logger) *SaramaLogger {
+	return &SaramaLogger{logger: kafkaLogger(l, 2)}
+}
+
+func (s *SaramaLogger) Print(v ...any) {
+	s.log(fmt.Sprint(v...))
+}
+
+func (s *SaramaLogger) Printf(format string, v ...any) {
+	s.log(fmt.Sprintf(format, v...))
+}
+
+func (s *SaramaLogger) Println(v ...any) {
+	s.log(fmt.Sprintln(v...))
+}
+
+func (s *SaramaLogger) log(msg string) {
+	msg = strings.TrimSpace(msg)
+	lower := strings.ToLower(msg)
+	if strings.Contains(lower, "error") || strings.Contains(lower, "fail") {
+		s.logger.Warn(msg)
+		return
+	}
+	s.logger.Debug(msg)
+}
+
+// KgoLogger implements kgo.Logger
+type KgoLogger struct {
+	logger logger.Logger
+	level  kgo.LogLevel
+}
+
+var _ kgo.Logger = (*KgoLogger)(nil)
+
+// NewKgoLogger creates a logger for kgo.WithLogger logging through l the
+// messages at or above level. A nil l uses the global logger named "kafka".
+func NewKgoLogger(l logger.Logger, level kgo.LogLevel) *KgoLogger {
+	return &KgoLogger{logger: kafkaLogger(l, 1), level: level}
+}
+
+// Level returns the level franz-go logs at
+func (k *KgoLogger) Level() kgo.LogLevel {
+	return k.level
+}
+
+// Log logs a message with its key value pairs as fields
+func (k *KgoLogger) Log(level kgo.LogLevel, msg string, keyvals ...any) {
+	fields := logger.KeysAndValues(keyvals...)
+	switch level {
+	case kgo.LogLevelError:
+		k.logger.Error(msg, fields...)
+	case kgo.LogLevelWarn:
+		k.logger.Warn(msg, fields...)
+	case kgo.LogLevelInfo:
+		k.logger.Info(msg, fields...)
+	default:
+		k.logger.Debug(msg, fields...)
+	}
+}
+
+// PrintfLogger implements kafka.Logger of segmentio/kafka-go, logging each
+// message at a fixed level
+type PrintfLogger struct {
+	logger logger.Logger
+	level  string
+}
+
+// NewPrintfLogger creates a logger for the Logger and ErrorLogger of
+// kafka-go readers and writers, logging at the named level through l. A nil
+// l uses the global logger named "kafka".
+func NewPrintfLogger(l logger.Logger, level string) *PrintfLogger {
+	return &PrintfLogger{logger: kafkaLogger(l, 1), level: level}
+}
+
+func (p *PrintfLogger) Printf(format string, v ...any) {
+	p.logger.Log(p.level, strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
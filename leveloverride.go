@@ -0,0 +1,128 @@
+package logger
+
+import (
+	"fmt"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// levelOverride is a compiled LevelOverride
+type levelOverride struct {
+	match entryMatcher
+	level zapcore.Level
+}
+
+// compileLevelOverride compiles a level override
+func compileLevelOverride(o LevelOverride) (levelOverride, error) {
+	m, err := newEntryMatcher(o.Message, o.Logger, o.Level, o.Field, o.FieldValue)
+	if err != nil {
+		return levelOverride{}, err
+	}
+	level, err := ParseLevel(o.To)
+	if err != nil {
+		return levelOverride{}, fmt.Errorf("logger: invalid level override: %w", err)
+	}
+	// The entry is already being written: it can no longer panic or exit
+	if severityOf(level) > zapcore.ErrorLevel {
+		return levelOverride{}, fmt.Errorf("logger: level override to %q: overrides cannot promote entries above error", o.To)
+	}
+	return levelOverride{match: m, level: level}, nil
+}
+
+// levelOverrideCore changes the level of the entries matching an override.
+// It runs right after the level check, and checks the new level against the
+// level of the logger name again, so that a demoted entry is dropped when
+// its new level is disabled. The first matching override applies. The
+// overrides are read from the rule set on every entry.
+type levelOverrideCore struct {
+	zapcore.Core
+	rules  *RuleSet
+	levels *namedLevels
+
+	// fields are the fields added through With, matched along with the
+	// entry fields
+	fields []zapcore.Field
+}
+
+func newLevelOverrideCore(core zapcore.Core, rules *RuleSet, levels *namedLevels) zapcore.Core {
+	return &levelOverrideCore{Core: core, rules: rules, levels: levels}
+}
+
+func (c *levelOverrideCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	clone.fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	return &clone
+}
+
+func (c *levelOverrideCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	// Overrides without field condition are applied here; the others once
+	// the fields are known
+	var candidates []levelOverride
+	for _, o := range c.rules.load().overrides {
+		if !o.match.matchEntry(ent) {
+			continue
+		}
+		if o.match.field == "" && len(candidates) == 0 {
+			if ent, ok := c.apply(ent, o.level); ok {
+				return c.Core.Check(ent, ce)
+			}
+			return ce
+		}
+		candidates = append(candidates, o)
+	}
+	if len(candidates) == 0 {
+		return c.Core.Check(ent, ce)
+	}
+	return ce.AddCore(ent, &levelOverrideWriter{core: c, candidates: candidates})
+}
+
+// apply sets the level of an entry, and reports whether the new level is
+// enabled for its logger name. Entries demoted below error lose the stack
+// trace added for their original level.
+func (c *levelOverrideCore) apply(ent zapcore.Entry, level zapcore.Level) (zapcore.Entry, bool) {
+	if rankOf(level) < rankOf(zapcore.ErrorLevel) {
+		ent.Stack = ""
+	}
+	ent.Level = level
+	return ent, rankOf(level) >= rankOf(c.levels.Level(ent.LoggerName))
+}
+
+// levelOverrideWriter is a single-use core that applies the first override
+// whose field condition matches, then checks and writes the entry through
+// the wrapped core
+type levelOverrideWriter struct {
+	core       *levelOverrideCore
+	candidates []levelOverride
+}
+
+func (w *levelOverrideWriter) Enabled(zapcore.Level) bool { return true }
+
+func (w *levelOverrideWriter) With([]zapcore.Field) zapcore.Core { return w }
+
+func (w *levelOverrideWriter) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, w)
+}
+
+func (w *levelOverrideWriter) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	all := fields
+	if len(w.core.fields) > 0 {
+		all = append(w.core.fields[:len(w.core.fields):len(w.core.fields)], fields...)
+	}
+	for _, o := range w.candidates {
+		if !o.match.matchFields(all) {
+			continue
+		}
+		var enabled bool
+		if ent, enabled = w.core.apply(ent, o.level); !enabled {
+			return nil
+		}
+		break
+	}
+	if checked := w.core.Core.Check(ent, nil); checked != nil {
+		return writeChecked(checked, ent, fields)
+	}
+	return nil
+}
+
+func (w *levelOverrideWriter) Sync() error { return nil }
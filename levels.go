@@ -0,0 +1,249 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelDefinition describes a user-defined level such as NOTICE or AUDIT
+type LevelDefinition struct {
+	// Name is the lowercase level name used in Config.Level and Log()
+	Name string
+
+	// Level is the zapcore.Level allocated for this definition
+	Level zapcore.Level
+
+	// Severity is the standard level this level behaves as. Sinks that only
+	// understand the standard levels (and the standard level filters) see
+	// entries at this level. A custom level is ordered just above its
+	// severity and after any custom level registered earlier with the same
+	// severity, e.g. info < notice < warn.
+	Severity zapcore.Level
+
+	rank int
+}
+
+// Custom levels are allocated below zap's lowest standard level so they never
+// collide with levels zap may add at the top of the range.
+const firstCustomLevel = zapcore.DebugLevel - 1
+
+var levelRegistry = struct {
+	sync.RWMutex
+	byName  map[string]LevelDefinition
+	byLevel map[zapcore.Level]LevelDefinition
+	next    zapcore.Level
+}{
+	byName:  map[string]LevelDefinition{},
+	byLevel: map[zapcore.Level]LevelDefinition{},
+	next:    firstCustomLevel,
+}
+
+// RegisterLevel registers a custom level that behaves as the given standard
+// severity for filtering. It returns the allocated zapcore.Level, which can
+// also be passed to zap APIs directly.
+func RegisterLevel(name string, severity zapcore.Level) (zapcore.Level, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return 0, fmt.Errorf("logger: level name must not be empty")
+	}
+	if _, err := zapcore.ParseLevel(name); err == nil {
+		return 0, fmt.Errorf("logger: level %q is a standard level", name)
+	}
+	if severity < zapcore.DebugLevel || severity > zapcore.FatalLevel {
+		return 0, fmt.Errorf("logger: invalid severity %v for level %q", severity, name)
+	}
+
+	levelRegistry.Lock()
+	defer levelRegistry.Unlock()
+
+	if _, exists := levelRegistry.byName[name]; exists {
+		return 0, fmt.Errorf("logger: level %q is already registered", name)
+	}
+	if levelRegistry.next == zapcore.Level(-128) {
+		return 0, fmt.Errorf("logger: too many custom levels")
+	}
+
+	// Order after the severity and any custom level already sharing it
+	rank := standardRank(severity) + 1
+	for _, def := range levelRegistry.byName {
+		if def.Severity == severity && def.rank >= rank {
+			rank = def.rank + 1
+		}
+	}
+
+	def := LevelDefinition{
+		Name:     name,
+		Level:    levelRegistry.next,
+		Severity: severity,
+		rank:     rank,
+	}
+	levelRegistry.byName[name] = def
+	levelRegistry.byLevel[def.Level] = def
+	levelRegistry.next--
+
+	return def.Level, nil
+}
+
+// Levels returns the registered custom level definitions
+func Levels() []LevelDefinition {
+	levelRegistry.RLock()
+	defer levelRegistry.RUnlock()
+
+	defs := make([]LevelDefinition, 0, len(levelRegistry.byName))
+	for _, def := range levelRegistry.byName {
+		defs = append(defs, def)
+	}
+	return defs
+}
+
+// ParseLevel parses a standard or registered custom level name
+func ParseLevel(name string) (zapcore.Level, error) {
+	name = strings.ToLower(name)
+	if level, err := zapcore.ParseLevel(name); err == nil {
+		return level, nil
+	}
+
+	levelRegistry.RLock()
+	defer levelRegistry.RUnlock()
+	if def, ok := levelRegistry.byName[name]; ok {
+		return def.Level, nil
+	}
+	return 0, fmt.Errorf("logger: unknown level %q", name)
+}
+
+// customLevel returns the definition of a custom level
+func customLevel(l zapcore.Level) (LevelDefinition, bool) {
+	if l >= zapcore.DebugLevel {
+		return LevelDefinition{}, false
+	}
+	levelRegistry.RLock()
+	defer levelRegistry.RUnlock()
+	def, ok := levelRegistry.byLevel[l]
+	return def, ok
+}
+
+// severityOf maps a level to the standard level it behaves as
+func severityOf(l zapcore.Level) zapcore.Level {
+	if def, ok := customLevel(l); ok {
+		return def.Severity
+	}
+	return l
+}
+
+// standardRank leaves room between standard levels for custom levels
+func standardRank(l zapcore.Level) int {
+	return int(l) * 1000
+}
+
+// rankOf returns the position of a level in the total level ordering
+func rankOf(l zapcore.Level) int {
+	if def, ok := customLevel(l); ok {
+		return def.rank
+	}
+	return standardRank(l)
+}
+
+// levelName returns the lowercase name of a standard or custom level
+func levelName(l zapcore.Level) string {
+	if def, ok := customLevel(l); ok {
+		return def.Name
+	}
+	return l.String()
+}
+
+// customLevelCore lets custom levels flow through cores that only understand
+// the standard levels. Cores below it see the entry's severity while checking,
+// and the encoders still receive the custom level so its name is written.
+type customLevelCore struct {
+	zapcore.Core
+}
+
+func newCustomLevelCore(core zapcore.Core) zapcore.Core {
+	return &customLevelCore{Core: core}
+}
+
+func (c *customLevelCore) Enabled(l zapcore.Level) bool {
+	return c.Core.Enabled(severityOf(l))
+}
+
+func (c *customLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &customLevelCore{Core: c.Core.With(fields)}
+}
+
+func (c *customLevelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	def, ok := customLevel(ent.Level)
+	if !ok {
+		return c.Core.Check(ent, ce)
+	}
+
+	if ce != nil {
+		// Other cores already claimed the entry; route writes through us
+		if c.Core.Enabled(def.Severity) {
+			return ce.AddCore(ent, c)
+		}
+		return ce
+	}
+
+	translated := ent
+	translated.Level = def.Severity
+	checked := c.Core.Check(translated, nil)
+	if checked != nil && checked.Entry.Level == def.Severity {
+		checked.Entry.Level = ent.Level
+	}
+	return checked
+}
+
+// customLevelEncoder renders custom levels in the style of the base encoder,
+// e.g. "NOTICE" in the color of INFO when base is a capital color encoder
+func customLevelEncoder(base zapcore.LevelEncoder) zapcore.LevelEncoder {
+	if base == nil {
+		base = zapcore.LowercaseLevelEncoder
+	}
+
+	var cache sync.Map // zapcore.Level -> string
+	return func(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		def, ok := customLevel(l)
+		if !ok {
+			base(l, enc)
+			return
+		}
+		if s, ok := cache.Load(l); ok {
+			enc.AppendString(s.(string))
+			return
+		}
+
+		s := renderCustomLevel(base, def)
+		cache.Store(l, s)
+		enc.AppendString(s)
+	}
+}
+
+// renderCustomLevel encodes the severity with base and swaps in the custom name
+func renderCustomLevel(base zapcore.LevelEncoder, def LevelDefinition) string {
+	m := zapcore.NewMapObjectEncoder()
+	_ = m.AddArray("level", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+		base(def.Severity, arr)
+		return nil
+	}))
+
+	elems, _ := m.Fields["level"].([]interface{})
+	if len(elems) == 0 {
+		return def.Name
+	}
+	rendered, ok := elems[0].(string)
+	if !ok {
+		return def.Name
+	}
+
+	switch {
+	case strings.Contains(rendered, def.Severity.CapitalString()):
+		return strings.Replace(rendered, def.Severity.CapitalString(), strings.ToUpper(def.Name), 1)
+	case strings.Contains(rendered, def.Severity.String()):
+		return strings.Replace(rendered, def.Severity.String(), def.Name, 1)
+	default:
+		return def.Name
+	}
+}
@@ -1,7 +1,13 @@
 package logger
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // Logger interface for dependency injection
@@ -12,13 +18,49 @@ type Logger interface {
 	Error(msg string, fields ...zap.Field)
 	Fatal(msg string, fields ...zap.Field)
 	Panic(msg string, fields ...zap.Field)
+	Log(level string, msg string, fields ...zap.Field)
 	With(fields ...zap.Field) Logger
+	WithFields(fields map[string]any) Logger
+	Named(name string) Logger
 	Sync() error
 }
 
 // ZapLogger wraps zap.Logger to implement our Logger interface
 type ZapLogger struct {
 	logger *zap.Logger
+	levels *namedLevels
+
+	// closers release background resources such as the async worker
+	closers []func() error
+
+	// rotators force the rotation of the rotating log files
+	rotators []func() error
+
+	// audit writes the audit events, if an audit file is configured
+	audit *auditLogger
+
+	// rules holds the drop filters, level overrides and routes
+	rules *RuleSet
+
+	// sampling holds the sampler, whose options can change at runtime
+	sampling *samplingState
+
+	// repanic makes Recover panic again after logging
+	repanic bool
+
+	// exit ends the process after an entry of a custom fatal level
+	exit func(code int)
+
+	// report receives the logger's own errors, such as the unknown level
+	// names given to Log and Check
+	report func(error)
+}
+
+// clone returns a copy of the logger wrapping the given zap logger
+func (l *ZapLogger) clone(logger *zap.Logger) *ZapLogger {
+	c := *l
+	c.logger = logger
+	return &c
 }
 
 // Implementation of Logger interface
@@ -47,11 +89,211 @@ func (l *ZapLogger) Panic(msg string, fields ...zap.Field) {
 	l.logger.Panic(msg, fields...)
 }
 
+// Log logs at the named standard or custom level. A custom level behaves
+// like its severity: error and above add a stack trace, panic panics and
+// fatal exits after writing. An unknown name is reported to
+// Config.OnInternalError and logs at info.
+func (l *ZapLogger) Log(level string, msg string, fields ...zap.Field) {
+	lvl := l.parseLevel(level)
+	if ce := l.terminate(l.logger.Check(lvl, msg), lvl, msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// parseLevel parses the level given to Log and Check, reporting an unknown
+// name and using info instead
+func (l *ZapLogger) parseLevel(level string) zapcore.Level {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		if l.report != nil {
+			l.report(fmt.Errorf("logger: unknown level %q, logged at info", level))
+		}
+		return zapcore.InfoLevel
+	}
+	return lvl
+}
+
+// terminate adds the panic or exit of the severity of a custom level, which
+// zap only applies to its own panic and fatal levels
+func (l *ZapLogger) terminate(ce *zapcore.CheckedEntry, lvl zapcore.Level, msg string) *zapcore.CheckedEntry {
+	def, ok := customLevel(lvl)
+	if !ok {
+		return ce
+	}
+	var hook zapcore.CheckWriteHook
+	switch def.Severity {
+	case zapcore.PanicLevel:
+		hook = zapcore.WriteThenPanic
+	case zapcore.FatalLevel:
+		exit := l.exit
+		if exit == nil {
+			exit = os.Exit
+		}
+		hook = exitHook{exit: exit}
+	default:
+		return ce
+	}
+	ent := zapcore.Entry{LoggerName: l.logger.Name(), Time: time.Now(), Level: lvl, Message: msg}
+	return ce.After(ent, hook)
+}
+
+// CheckLogger is an optional extension of Logger, implemented by *ZapLogger,
+// for level-guarded logging. The package function Check uses it when the
+// global logger implements it.
+type CheckLogger interface {
+	Check(level string, msg string) *zapcore.CheckedEntry
+}
+
+// Check returns a CheckedEntry if an entry at level would be logged, or nil
+// otherwise, so that hot paths only build expensive fields when needed:
+//
+//	if ce := log.Check(logger.LevelDebug, "cache miss"); ce != nil {
+//		ce.Write(logger.Any("keys", keys))
+//	}
+//
+// Levels are handled like in Log: an unknown name is reported and treated
+// as info, and custom levels panic or exit like their severity.
+func (l *ZapLogger) Check(level string, msg string) *zapcore.CheckedEntry {
+	lvl := l.parseLevel(level)
+	return l.terminate(l.logger.Check(lvl, msg), lvl, msg)
+}
+
+// LevelEnabler is an optional extension of Logger, implemented by
+// *ZapLogger, reporting its effective level. The package functions Enabled
+// and Level use it when the global logger implements it.
+type LevelEnabler interface {
+	Enabled(level string) bool
+	Level() string
+}
+
+// Enabled reports whether an entry at level would be logged, taking the
+// level of the logger name into account. An invalid level is treated as
+// info, like Log.
+func (l *ZapLogger) Enabled(level string) bool {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		lvl = zapcore.InfoLevel
+	}
+	return l.logger.Core().Enabled(lvl) && rankOf(lvl) >= rankOf(l.levels.Level(l.logger.Name()))
+}
+
+// Level returns the effective level of the logger, i.e. the level of its
+// name after inheritance (see EffectiveLevel)
+func (l *ZapLogger) Level() string {
+	return l.EffectiveLevel(l.logger.Name())
+}
+
 func (l *ZapLogger) With(fields ...zap.Field) Logger {
-	return &ZapLogger{logger: l.logger.With(fields...)}
+	c := l.clone(l.logger.With(fields...))
+	if c.audit != nil {
+		audit := *c.audit
+		audit.core = audit.core.With(fields)
+		c.audit = &audit
+	}
+	return c
+}
+
+// WithFields creates a child logger with fields from a map
+func (l *ZapLogger) WithFields(fields map[string]any) Logger {
+	return l.With(Fields(fields)...)
+}
+
+// Named adds a name segment to the logger. Names are joined with dots and
+// form a hierarchy: a level set for "server.http" also applies to
+// "server.http.router" unless it has its own level.
+func (l *ZapLogger) Named(name string) Logger {
+	return l.clone(l.logger.Named(name))
+}
+
+// Unwrap returns the underlying zap logger, for zap features this package
+// does not wrap such as zaptest, custom cores or zap options.
+//
+// The zap logger shares the configuration of l: its sinks, named levels,
+// sampling, rules and With fields apply, and level changes made through l
+// affect it. Its caller is the code calling it directly. The audit logger is
+// not part of it, and it must not outlive l.Close, which remains
+// responsible for releasing the resources.
+func (l *ZapLogger) Unwrap() *zap.Logger {
+	// Undo the skip of the wrapper methods
+	return l.logger.WithOptions(zap.AddCallerSkip(-1))
+}
+
+// Sugar returns a zap SugaredLogger over the underlying zap logger, for
+// code written against zap's sugared API, e.g. during a migration. The
+// contract of Unwrap applies.
+func (l *ZapLogger) Sugar() *zap.SugaredLogger {
+	return l.Unwrap().Sugar()
+}
+
+// SetLevel changes the root level at runtime for loggers without a named override
+func (l *ZapLogger) SetLevel(level string) error {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	l.levels.SetRoot(lvl)
+	return nil
+}
+
+// SetNamedLevel changes the level of a named logger at runtime
+func (l *ZapLogger) SetNamedLevel(name, level string) error {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	l.levels.Set(name, lvl)
+	return nil
+}
+
+// EffectiveLevel returns the level applied to a logger name after inheritance
+func (l *ZapLogger) EffectiveLevel(name string) string {
+	return levelName(l.levels.Level(name))
+}
+
+// UnsetNamedLevel removes the level override of a named logger
+func (l *ZapLogger) UnsetNamedLevel(name string) {
+	l.levels.Unset(name)
 }
 
 func (l *ZapLogger) Sync() error {
 	return l.logger.Sync()
 }
+
+// Close flushes buffered entries and releases background resources. The
+// logger and its children keep working afterwards, but write synchronously.
+func (l *ZapLogger) Close() error {
+	errs := []error{l.Sync()}
+	// Release in reverse order of creation, so that queued entries reach
+	// the writers before these are stopped
+	for i := len(l.closers) - 1; i >= 0; i-- {
+		errs = append(errs, l.closers[i]())
+	}
+	return errors.Join(errs...)
+}
+
+// Rotate forces a rotation of the rotating log files, for logrotate-style
+// tooling or admin endpoints. Post-rotation hooks and archiving run as for
+// any rotation.
+func (l *ZapLogger) Rotate() error {
+	if len(l.rotators) == 0 {
+		return errNoRotatingFile
+	}
+	var errs []error
+	for _, rotate := range l.rotators {
+		errs = append(errs, rotate())
+	}
+	return errors.Join(errs...)
+}
+
+// RotateOnSignal rotates the log files whenever one of the signals is
+// received, SIGUSR1 by default. Call stop to stop handling them. Rotation
+// errors go to Config.OnInternalError.
+func (l *ZapLogger) RotateOnSignal(sigs ...os.Signal) (stop func()) {
+	report := l.report
+	if report == nil {
+		report = Config{}.internalErrorHandler()
+	}
+	return rotateOnSignal(l.Rotate, report, sigs)
+}
+
 // Enhanced scope detection test
@@ -0,0 +1,116 @@
+// Package logtest provides loggers for unit tests. NewLogger writes through
+// t.Log, NewObserved records entries in memory, NewMock records calls and
+// checks expectations, and NewGolden compares log output with golden files,
+// for teams that treat their log schema as a contract.
+//
+//	func TestOrderLogs(t *testing.T) {
+//		log, golden := logtest.NewGolden(t, logger.ProductionConfig(), "order_id")
+//		NewOrderService(log).Create(order)
+//		golden.Assert("order.golden")
+//	}
+//
+// Run go test -logtest.update to write the golden files under testdata.
+package logtest
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/csmart-libs/go-logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var update = flag.Bool("logtest.update", false, "rewrite the golden files of logtest")
+
+// GoldenTime is the time of every entry written by a golden logger
+var GoldenTime = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// NormalizedValue replaces the values of normalized fields
+const NormalizedValue = "<normalized>"
+
+// Golden holds the output of a logger created by NewGolden
+type Golden struct {
+	t   testing.TB
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// NewGolden creates a logger with the given configuration writing to
+// memory, with the time of every entry set to GoldenTime and without caller
+// or stack traces, so that its output is the same on every run. The values
+// of the fields named in normalize, such as IDs or durations, are replaced
+// with NormalizedValue, or with the configured redaction mask if redaction
+// is enabled.
+func NewGolden(t testing.TB, config logger.Config, normalize ...string) (logger.Logger, *Golden) {
+	t.Helper()
+	config.DisableCaller = true
+	config.DisableStacktrace = true
+	config.Timezone = "UTC"
+	if len(normalize) > 0 {
+		if !config.Redaction.Enabled {
+			config.Redaction = logger.RedactionOptions{Enabled: true, Mask: NormalizedValue}
+		}
+		keys := append([]string(nil), config.Redaction.Keys...)
+		config.Redaction.Keys = append(keys, normalize...)
+	}
+
+	g := &Golden{t: t}
+	l, err := logger.NewLoggerWithWriter(config, g, zap.WithClock(fixedClock{}))
+	if err != nil {
+		t.Fatalf("logtest: create golden logger: %v", err)
+	}
+	return l, g
+}
+
+// Write appends encoded entries to the output
+func (g *Golden) Write(p []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.buf.Write(p)
+}
+
+// Output returns the output written so far
+func (g *Golden) Output() []byte {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return bytes.Clone(g.buf.Bytes())
+}
+
+// Assert fails the test if the output differs from the golden file
+// testdata/name. With the -logtest.update flag, it writes the file instead.
+func (g *Golden) Assert(name string) {
+	g.t.Helper()
+	path := filepath.Join("testdata", name)
+	got := g.Output()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			g.t.Fatalf("logtest: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			g.t.Fatalf("logtest: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		g.t.Fatalf("logtest: %v (run go test -logtest.update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		g.t.Errorf("logtest: output differs from %s (run go test -logtest.update to accept it)\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// fixedClock is a zapcore.Clock stopped at GoldenTime
+type fixedClock struct{}
+
+func (fixedClock) Now() time.Time { return GoldenTime }
+
+func (fixedClock) NewTicker(d time.Duration) *time.Ticker { return time.NewTicker(d) }
+
+var _ zapcore.Clock = fixedClock{}
@@ -0,0 +1,49 @@
+package logtest
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/csmart-libs/go-logger"
+)
+
+// NewLogger creates a logger writing through t.Log at the given level
+// (debug if empty or invalid), so that its output interleaves with the test
+// output under go test -v and is only shown for failing tests otherwise.
+// Entries carry the caller of the log method, and Fatal and custom
+// fatal levels fail the test with t.FailNow instead of exiting the process.
+func NewLogger(t testing.TB, level string) logger.Logger {
+	config := logger.TestConfig()
+	config.Level = logger.LevelDebug
+	if _, err := logger.ParseLevel(level); err == nil && level != "" {
+		config.Level = level
+	}
+	config.Colors.Mode = logger.ColorModeNever
+	config.ExitFunc = func(int) { t.FailNow() }
+
+	w := &testWriter{t: t}
+	t.Cleanup(func() { w.done.Store(true) })
+
+	l, err := logger.NewLoggerWithWriter(config, w)
+	if err != nil {
+		t.Fatalf("logtest: create test logger: %v", err)
+	}
+	return l
+}
+
+// testWriter writes each entry through t.Log
+type testWriter struct {
+	t testing.TB
+
+	// done is set once the test has finished, when t.Log would panic
+	done atomic.Bool
+}
+
+func (w *testWriter) Write(p []byte) (int, error) {
+	if !w.done.Load() {
+		w.t.Helper()
+		w.t.Log(strings.TrimSuffix(string(p), "\n"))
+	}
+	return len(p), nil
+}
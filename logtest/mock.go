@@ -0,0 +1,254 @@
+package logtest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/csmart-libs/go-logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Call is a logging call recorded by a Mock. Fields holds the fields added
+// through With, then the fields passed to the call, then the context fields
+// for the package functions such as logger.InfoContext, in the order of
+// the entries of a logger.ZapLogger.
+type Call struct {
+	Level   string
+	Logger  string
+	Message string
+	Fields  []zap.Field
+}
+
+// Field returns the last field of the call with the given key
+func (c Call) Field(key string) (zap.Field, bool) {
+	for i := len(c.Fields) - 1; i >= 0; i-- {
+		if c.Fields[i].Key == key {
+			return c.Fields[i], true
+		}
+	}
+	return zap.Field{}, false
+}
+
+// Mock is a logger.Logger recording its calls in memory, so that code
+// depending on the interface can be unit tested without zap internals.
+// Loggers derived through With, WithFields and Named record into the same
+// list. Fatal is recorded without exiting and Panic panics after recording.
+//
+//	log := logtest.NewMock()
+//	log.Expect(logger.LevelWarn, "retrying").WithFields(logger.Int("attempt", 2))
+//	NewClient(log).Fetch()
+//	log.AssertExpectations(t)
+type Mock struct {
+	rec    *recorder
+	name   string
+	fields []zap.Field
+}
+
+// recorder holds the state shared by a Mock and its children
+type recorder struct {
+	mu           sync.Mutex
+	calls        []Call
+	expectations []*Expectation
+}
+
+// NewMock creates a Mock with no recorded calls
+func NewMock() *Mock {
+	return &Mock{rec: &recorder{}}
+}
+
+func (m *Mock) record(level string, msg string, fields []zap.Field) {
+	all := make([]zap.Field, 0, len(m.fields)+len(fields))
+	all = append(all, m.fields...)
+	all = append(all, fields...)
+
+	m.rec.mu.Lock()
+	defer m.rec.mu.Unlock()
+	m.rec.calls = append(m.rec.calls, Call{Level: level, Logger: m.name, Message: msg, Fields: all})
+}
+
+func (m *Mock) Debug(msg string, fields ...zap.Field) { m.record(logger.LevelDebug, msg, fields) }
+func (m *Mock) Info(msg string, fields ...zap.Field)  { m.record(logger.LevelInfo, msg, fields) }
+func (m *Mock) Warn(msg string, fields ...zap.Field)  { m.record(logger.LevelWarn, msg, fields) }
+func (m *Mock) Error(msg string, fields ...zap.Field) { m.record(logger.LevelError, msg, fields) }
+func (m *Mock) Fatal(msg string, fields ...zap.Field) { m.record(logger.LevelFatal, msg, fields) }
+
+func (m *Mock) Panic(msg string, fields ...zap.Field) {
+	m.record(logger.LevelPanic, msg, fields)
+	panic(msg)
+}
+
+func (m *Mock) Log(level string, msg string, fields ...zap.Field) {
+	m.record(strings.ToLower(level), msg, fields)
+}
+
+// Check always returns an entry recording the call when written
+func (m *Mock) Check(level string, msg string) *zapcore.CheckedEntry {
+	lvl, err := logger.ParseLevel(level)
+	if err != nil {
+		lvl = zapcore.InfoLevel
+	}
+	ent := zapcore.Entry{Level: lvl, LoggerName: m.name, Message: msg, Time: time.Now()}
+	return (*zapcore.CheckedEntry)(nil).AddCore(ent, mockCore{m: m, level: strings.ToLower(level)})
+}
+
+// Enabled always returns true, as every call is recorded
+func (m *Mock) Enabled(string) bool { return true }
+
+// Level always returns debug, as every call is recorded
+func (m *Mock) Level() string { return logger.LevelDebug }
+
+func (m *Mock) With(fields ...zap.Field) logger.Logger {
+	merged := make([]zap.Field, 0, len(m.fields)+len(fields))
+	merged = append(merged, m.fields...)
+	merged = append(merged, fields...)
+	return &Mock{rec: m.rec, name: m.name, fields: merged}
+}
+
+func (m *Mock) WithFields(fields map[string]any) logger.Logger {
+	return m.With(logger.Fields(fields)...)
+}
+
+func (m *Mock) Named(name string) logger.Logger {
+	if m.name != "" {
+		name = m.name + "." + name
+	}
+	return &Mock{rec: m.rec, name: name, fields: m.fields}
+}
+
+// WithOptions returns the logger unchanged, as every call is recorded
+func (m *Mock) WithOptions(...logger.Option) logger.Logger { return m }
+
+func (m *Mock) Sync() error { return nil }
+
+// mockCore records the entries written through CheckedEntries returned by
+// Mock.Check
+type mockCore struct {
+	m     *Mock
+	level string
+}
+
+func (c mockCore) Enabled(zapcore.Level) bool        { return true }
+func (c mockCore) With([]zapcore.Field) zapcore.Core { return c }
+func (c mockCore) Sync() error                       { return nil }
+
+func (c mockCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+func (c mockCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.m.record(c.level, ent.Message, fields)
+	return nil
+}
+
+// Calls returns the calls recorded so far, oldest first
+func (m *Mock) Calls() []Call {
+	m.rec.mu.Lock()
+	defer m.rec.mu.Unlock()
+	return append([]Call(nil), m.rec.calls...)
+}
+
+// CallsAt returns the calls recorded at level, oldest first
+func (m *Mock) CallsAt(level string) []Call {
+	var calls []Call
+	for _, call := range m.Calls() {
+		if call.Level == level {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset drops the recorded calls and expectations
+func (m *Mock) Reset() {
+	m.rec.mu.Lock()
+	defer m.rec.mu.Unlock()
+	m.rec.calls = nil
+	m.rec.expectations = nil
+}
+
+// Expectation is a call expected by AssertExpectations, added with
+// Mock.Expect
+type Expectation struct {
+	level  string
+	msg    string
+	fields []zap.Field
+	times  int
+}
+
+// Expect adds an expectation that a call with the given level and message
+// is made at least once
+func (m *Mock) Expect(level, msg string) *Expectation {
+	e := &Expectation{level: level, msg: msg, times: -1}
+	m.rec.mu.Lock()
+	defer m.rec.mu.Unlock()
+	m.rec.expectations = append(m.rec.expectations, e)
+	return e
+}
+
+// WithFields restricts the expectation to calls carrying all the given
+// fields
+func (e *Expectation) WithFields(fields ...zap.Field) *Expectation {
+	e.fields = append(e.fields, fields...)
+	return e
+}
+
+// Times expects exactly n matching calls; 0 expects none
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+// matches reports whether call satisfies the expectation
+func (e *Expectation) matches(call Call) bool {
+	if call.Level != e.level || call.Message != e.msg {
+		return false
+	}
+	for _, want := range e.fields {
+		got, ok := call.Field(want.Key)
+		if !ok || !got.Equals(want) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *Expectation) String() string {
+	s := fmt.Sprintf("%s %q", e.level, e.msg)
+	for _, f := range e.fields {
+		s += " " + f.Key
+	}
+	return s
+}
+
+// AssertExpectations reports every unmet expectation as a test error and
+// returns whether all were met
+func (m *Mock) AssertExpectations(t testing.TB) bool {
+	t.Helper()
+	calls := m.Calls()
+	m.rec.mu.Lock()
+	expectations := append([]*Expectation(nil), m.rec.expectations...)
+	m.rec.mu.Unlock()
+
+	ok := true
+	for _, e := range expectations {
+		n := 0
+		for _, call := range calls {
+			if e.matches(call) {
+				n++
+			}
+		}
+		switch {
+		case e.times < 0 && n == 0:
+			t.Errorf("logtest: expected call %s was not made", e)
+			ok = false
+		case e.times >= 0 && n != e.times:
+			t.Errorf("logtest: expected call %s %d times, got %d", e, e.times, n)
+			ok = false
+		}
+	}
+	return ok
+}
@@ -0,0 +1,43 @@
+package logtest
+
+import (
+	"os"
+
+	"github.com/csmart-libs/go-logger"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// ObservedLogs holds the entries recorded by a logger created with
+// NewObserved. FilterMessage, FilterField, FilterLevelExact, Len, All and
+// TakeAll let tests assert on them.
+type ObservedLogs = observer.ObservedLogs
+
+// LoggedEntry is an entry recorded by NewObserved, with its context fields
+type LoggedEntry = observer.LoggedEntry
+
+// NewObserved creates a logger recording the entries at or above level
+// (debug if empty or invalid) in memory, so that unit tests can assert
+// that specific structured entries were or were not emitted.
+//
+//	log, logs := logtest.NewObserved("info")
+//	svc := NewService(log)
+//	svc.Run()
+//	if logs.FilterMessage("job done").FilterField(logger.Int("jobs", 3)).Len() != 1 {
+//		t.Error("missing job done entry")
+//	}
+func NewObserved(level string) (logger.Logger, *ObservedLogs) {
+	config := logger.TestConfig()
+	config.Level = logger.LevelDebug
+	if _, err := logger.ParseLevel(level); err == nil && level != "" {
+		config.Level = level
+	}
+	config.OutputPaths = []string{os.DevNull}
+	core, logs := observer.New(zapcore.DebugLevel)
+	l, err := logger.NewLoggerWithCores(config, core)
+	if err != nil {
+		// The test configuration is always valid
+		panic(err)
+	}
+	return l, logs
+}
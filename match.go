@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// entryMatcher matches entries on their message, logger name, level and
+// fields, for DropFilter and LevelOverride
+type entryMatcher struct {
+	message    *regexp.Regexp
+	logger     string
+	hasLevel   bool
+	level      zapcore.Level
+	field      string
+	fieldValue *regexp.Regexp
+}
+
+// newEntryMatcher compiles the conditions of a matcher; at least one must
+// be set
+func newEntryMatcher(message, logger, level, field, fieldValue string) (entryMatcher, error) {
+	if message == "" && logger == "" && level == "" && field == "" {
+		return entryMatcher{}, fmt.Errorf("logger: entry filter without condition")
+	}
+	if fieldValue != "" && field == "" {
+		return entryMatcher{}, fmt.Errorf("logger: entry filter has a field value without field")
+	}
+	m := entryMatcher{logger: logger, field: field}
+	var err error
+	if level != "" {
+		if m.level, err = ParseLevel(level); err != nil {
+			return entryMatcher{}, fmt.Errorf("logger: entry filter has unknown level %q", level)
+		}
+		m.hasLevel = true
+	}
+	if message != "" {
+		if m.message, err = regexp.Compile(message); err != nil {
+			return entryMatcher{}, fmt.Errorf("logger: invalid message pattern %q: %w", message, err)
+		}
+	}
+	if fieldValue != "" {
+		if m.fieldValue, err = regexp.Compile(fieldValue); err != nil {
+			return entryMatcher{}, fmt.Errorf("logger: invalid field value pattern %q: %w", fieldValue, err)
+		}
+	}
+	return m, nil
+}
+
+// matchEntry reports whether the entry matches the message, logger and
+// level conditions of the matcher
+func (f entryMatcher) matchEntry(ent zapcore.Entry) bool {
+	if f.hasLevel && ent.Level != f.level {
+		return false
+	}
+	if f.logger != "" && ent.LoggerName != f.logger && !strings.HasPrefix(ent.LoggerName, f.logger+".") {
+		return false
+	}
+	return f.message == nil || f.message.MatchString(ent.Message)
+}
+
+// matchFields reports whether fields match the field condition of the
+// matcher
+func (f entryMatcher) matchFields(fields []zapcore.Field) bool {
+	if f.field == "" {
+		return true
+	}
+	value, ok := fieldValue(fields, f.field)
+	return ok && (f.fieldValue == nil || f.fieldValue.MatchString(value))
+}
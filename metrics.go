@@ -0,0 +1,129 @@
+package logger
+
+import (
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Reasons passed to MetricsRecorder.RecordDropped
+const (
+	DropReasonSampled     = "sampled"
+	DropReasonRateLimited = "rate_limited"
+	DropReasonBreaker     = "circuit_breaker"
+	DropReasonQueueFull   = "queue_full"
+	DropReasonDiskFull    = "disk_full"
+	DropReasonSinkFailed  = "sink_failed"
+	DropReasonFiltered    = "filtered"
+)
+
+// MetricsRecorder receives metrics about the entries a logger emits.
+// Implementations must be safe for concurrent use; see the prommetrics
+// package for a Prometheus implementation.
+type MetricsRecorder interface {
+	// RecordEntry is called for every entry written, with its level and
+	// logger name. Custom levels are reported by their severity.
+	RecordEntry(level, logger string)
+
+	// RecordWrite is called for every write to a sink
+	RecordWrite(sink string, bytes int, duration time.Duration, err error)
+
+	// RecordDropped is called when entries are dropped, with the reason
+	RecordDropped(reason string, n int)
+}
+
+// RotationRecorder is implemented by MetricsRecorders that also record the
+// rotations of file sinks
+type RotationRecorder interface {
+	RecordRotation(sink string, at time.Time)
+}
+
+// nopMetrics is the MetricsRecorder used when none is configured
+type nopMetrics struct{}
+
+func (nopMetrics) RecordEntry(string, string) {}
+
+func (nopMetrics) RecordWrite(string, int, time.Duration, error) {}
+
+func (nopMetrics) RecordDropped(string, int) {}
+
+// metrics returns the configured MetricsRecorder, or a no-op one
+func (c Config) metrics() MetricsRecorder {
+	if c.Metrics != nil {
+		return c.Metrics
+	}
+	return nopMetrics{}
+}
+
+// multiMetrics passes metrics to several recorders
+type multiMetrics []MetricsRecorder
+
+// combineMetrics returns a recorder passing metrics to all non-nil recorders
+func combineMetrics(recorders ...MetricsRecorder) MetricsRecorder {
+	var m multiMetrics
+	for _, r := range recorders {
+		if r != nil {
+			m = append(m, r)
+		}
+	}
+	if len(m) == 1 {
+		return m[0]
+	}
+	return m
+}
+
+func (m multiMetrics) RecordEntry(level, logger string) {
+	for _, r := range m {
+		r.RecordEntry(level, logger)
+	}
+}
+
+func (m multiMetrics) RecordWrite(sink string, bytes int, duration time.Duration, err error) {
+	for _, r := range m {
+		r.RecordWrite(sink, bytes, duration, err)
+	}
+}
+
+func (m multiMetrics) RecordDropped(reason string, n int) {
+	for _, r := range m {
+		r.RecordDropped(reason, n)
+	}
+}
+
+func (m multiMetrics) RecordRotation(sink string, at time.Time) {
+	for _, r := range m {
+		if rr, ok := r.(RotationRecorder); ok {
+			rr.RecordRotation(sink, at)
+		}
+	}
+}
+
+// newMetricsCore records every entry written through a core. It wraps the
+// sinks, so entries dropped on the way are not counted and custom levels are
+// recorded by their severity.
+func newMetricsCore(core zapcore.Core, metrics MetricsRecorder) zapcore.Core {
+	return newTransformCore(core, &entryTransform{
+		write: func(ent *zapcore.Entry, fields []zapcore.Field) ([]zapcore.Field, bool) {
+			metrics.RecordEntry(levelName(ent.Level), ent.LoggerName)
+			return fields, true
+		},
+	})
+}
+
+// metricsWriter records the writes to a sink
+type metricsWriter struct {
+	zapcore.WriteSyncer
+	sink    string
+	metrics MetricsRecorder
+}
+
+func newMetricsWriter(sink string, w zapcore.WriteSyncer, metrics MetricsRecorder) zapcore.WriteSyncer {
+	return &metricsWriter{WriteSyncer: w, sink: sink, metrics: metrics}
+}
+
+func (w *metricsWriter) Write(p []byte) (int, error) {
+	start := time.Now()
+	n, err := w.WriteSyncer.Write(p)
+	w.metrics.RecordWrite(w.sink, n, time.Since(start), err)
+	return n, err
+}
@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// namedLevels holds the root level and per-name level overrides of a logger
+// tree. Reads use an immutable snapshot so the logging path never locks.
+type namedLevels struct {
+	mu       sync.Mutex // serializes writers
+	snapshot atomic.Pointer[levelSnapshot]
+}
+
+type levelSnapshot struct {
+	root   zapcore.Level
+	levels map[string]zapcore.Level
+	min    zapcore.Level // lowest level enabled anywhere in the tree
+}
+
+// newNamedLevels creates the level registry from the configured levels
+func newNamedLevels(root zapcore.Level, levels map[string]string) (*namedLevels, error) {
+	parsed := make(map[string]zapcore.Level, len(levels))
+	for name, levelName := range levels {
+		level, err := ParseLevel(levelName)
+		if err != nil {
+			return nil, fmt.Errorf("logger: invalid level for %q: %w", name, err)
+		}
+		parsed[name] = level
+	}
+
+	r := &namedLevels{}
+	r.store(root, parsed)
+	return r, nil
+}
+
+// store publishes a new snapshot; callers must hold mu or own r exclusively
+func (r *namedLevels) store(root zapcore.Level, levels map[string]zapcore.Level) {
+	min := root
+	for _, level := range levels {
+		if rankOf(level) < rankOf(min) {
+			min = level
+		}
+	}
+	r.snapshot.Store(&levelSnapshot{root: root, levels: levels, min: min})
+}
+
+// update applies fn to a copy of the current overrides and publishes the result
+func (r *namedLevels) update(fn func(root *zapcore.Level, levels map[string]zapcore.Level)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := r.snapshot.Load()
+	root := current.root
+	levels := make(map[string]zapcore.Level, len(current.levels)+1)
+	for name, level := range current.levels {
+		levels[name] = level
+	}
+	fn(&root, levels)
+	r.store(root, levels)
+}
+
+// Level returns the effective level for a logger name. Names are
+// hierarchical: "server.http.router" inherits the level of "server.http",
+// then "server", then the root, unless it has its own override.
+func (r *namedLevels) Level(name string) zapcore.Level {
+	s := r.snapshot.Load()
+	if len(s.levels) == 0 {
+		return s.root
+	}
+	for name != "" {
+		if level, ok := s.levels[name]; ok {
+			return level
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return s.root
+}
+
+// SetRoot sets the level used by loggers without an override
+func (r *namedLevels) SetRoot(level zapcore.Level) {
+	r.update(func(root *zapcore.Level, _ map[string]zapcore.Level) {
+		*root = level
+	})
+}
+
+// Set sets the level of a named logger
+func (r *namedLevels) Set(name string, level zapcore.Level) {
+	r.update(func(_ *zapcore.Level, levels map[string]zapcore.Level) {
+		levels[name] = level
+	})
+}
+
+// Unset removes the override of a named logger
+func (r *namedLevels) Unset(name string) {
+	r.update(func(_ *zapcore.Level, levels map[string]zapcore.Level) {
+		delete(levels, name)
+	})
+}
+
+// enabled reports whether a level is enabled for any logger in the tree
+func (r *namedLevels) enabled(l zapcore.Level) bool {
+	return rankOf(l) >= rankOf(r.snapshot.Load().min)
+}
+
+// namedLevelCore filters entries by the level configured for their logger name
+type namedLevelCore struct {
+	zapcore.Core
+	levels *namedLevels
+}
+
+func newNamedLevelCore(core zapcore.Core, levels *namedLevels) zapcore.Core {
+	return &namedLevelCore{Core: core, levels: levels}
+}
+
+func (c *namedLevelCore) Enabled(l zapcore.Level) bool {
+	return c.levels.enabled(l) && c.Core.Enabled(l)
+}
+
+func (c *namedLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &namedLevelCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+func (c *namedLevelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if rankOf(ent.Level) < rankOf(c.levels.Level(ent.LoggerName)) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NopLogger is a Logger that drops every entry without allocating, for
+// benchmarks, optional dependencies and library defaults that should not
+// fall back to the global logger. Fatal and Panic still exit and panic.
+type NopLogger struct{}
+
+// Nop returns a Logger that drops every entry
+func Nop() Logger {
+	return NopLogger{}
+}
+
+func (NopLogger) Debug(string, ...zap.Field)                         {}
+func (NopLogger) Info(string, ...zap.Field)                          {}
+func (NopLogger) Warn(string, ...zap.Field)                          {}
+func (NopLogger) Error(string, ...zap.Field)                         {}
+func (NopLogger) Fatal(string, ...zap.Field)                         { os.Exit(1) }
+func (NopLogger) Panic(msg string, _ ...zap.Field)                   { panic(msg) }
+func (NopLogger) Log(string, string, ...zap.Field)                   {}
+func (NopLogger) Check(string, string) *zapcore.CheckedEntry         { return nil }
+func (NopLogger) Enabled(string) bool                                { return false }
+func (NopLogger) Level() string                                      { return "" }
+func (NopLogger) DebugContext(context.Context, string, ...zap.Field) {}
+func (NopLogger) InfoContext(context.Context, string, ...zap.Field)  {}
+func (NopLogger) WarnContext(context.Context, string, ...zap.Field)  {}
+func (NopLogger) ErrorContext(context.Context, string, ...zap.Field) {}
+func (n NopLogger) With(...zap.Field) Logger                         { return n }
+func (n NopLogger) WithFields(map[string]any) Logger                 { return n }
+func (n NopLogger) Named(string) Logger                              { return n }
+func (n NopLogger) WithOptions(...Option) Logger                     { return n }
+func (NopLogger) Sync() error                                        { return nil }
+
+// NewDiscardLogger creates a logger that processes entries like one built
+// from config, encoding included, but discards the output, e.g. to
+// benchmark the cost of logging
+func NewDiscardLogger(config Config) (Logger, error) {
+	return NewLoggerWithWriter(config, io.Discard)
+}
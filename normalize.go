@@ -0,0 +1,223 @@
+package logger
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Warning describes a setting that Normalize changed
+type Warning struct {
+	// Option names the setting after its JSON key, e.g. "file_options.compression"
+	Option string
+
+	// Value is the given value
+	Value string
+
+	// Applied is the value used instead
+	Applied string
+
+	// Reason explains the change
+	Reason string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s %q replaced with %q: %s", w.Option, w.Value, w.Applied, w.Reason)
+}
+
+// Normalize returns the configuration the constructors actually apply: they
+// build the logger from its result. Empty settings are filled with their
+// defaults and invalid ones replaced, with a warning for every replaced
+// value. Operators can log or display the warnings to see what became of
+// their input; ValidateStrict rejects such input instead. Invalid settings
+// that have no sensible replacement, such as the levels of named loggers,
+// outputs and files, the timezone or the rotation interval, time and week
+// start day of time rotation, are left as is and make the constructors
+// fail.
+func (c Config) Normalize() (Config, []Warning) {
+	n := &normalizer{}
+
+	if _, err := ParseLevel(c.Level); err != nil {
+		n.replace(&c.Level, "level", LevelInfo, "unknown level")
+	}
+	switch {
+	case c.Environment == "":
+		c.Environment = EnvDevelopment
+	case !validEnvironments[c.Environment]:
+		n.replace(&c.Environment, "environment", EnvDevelopment, "unknown environment")
+	}
+	switch {
+	case c.IsProduction() && c.Encoding != EncodingJSON:
+		n.replace(&c.Encoding, "encoding", EncodingJSON, "production always encodes json")
+	case c.Encoding == "":
+		c.Encoding = EncodingConsole
+	case !validEncodings[c.Encoding]:
+		n.replace(&c.Encoding, "encoding", EncodingConsole, "unknown encoding")
+	}
+	n.oneOf(&c.Colors.Mode, "colors.mode", ColorModeAuto, validColorModes)
+	n.oneOf(&c.TimeEncoding, "time_encoding", TimeEncodingISO8601, validTimeEncodings)
+	n.oneOf(&c.CallerFormat, "caller_format", CallerFormatShort, validCallerFormats)
+	c.FallbackOutput = strings.ToLower(c.FallbackOutput)
+	n.oneOf(&c.FallbackOutput, "fallback_output", FallbackStderr, validFallbackOutputs)
+	if len(c.OutputPaths) == 0 {
+		c.OutputPaths = []string{SinkStdout}
+	}
+	if len(c.Sinks) > 0 {
+		sinks := make(map[string]SinkOptions, len(c.Sinks))
+		for _, name := range slices.Sorted(maps.Keys(c.Sinks)) {
+			sink := c.Sinks[name]
+			n.encoding(&sink.Encoding, fmt.Sprintf("sinks[%s].encoding", name), "")
+			sinks[name] = sink
+		}
+		c.Sinks = sinks
+	}
+	n.encoding(&c.Tenants.Encoding, "tenants.encoding", "")
+	if c.Audit.Encoding == "" {
+		c.Audit.Encoding = EncodingJSON
+	}
+	n.encoding(&c.Audit.Encoding, "audit.encoding", EncodingJSON)
+
+	// Levels of the processing stages; empty selects their own default
+	n.level(&c.RateLimit.UnlimitedLevel, "rate_limit.unlimited_level")
+	n.level(&c.Backtrace.Level, "backtrace.level")
+	n.level(&c.CircuitBreaker.Level, "circuit_breaker.level")
+	c.Sampling = n.sampling(c.Sampling)
+
+	c.FileOptions = n.file("file_options", c.FileOptions)
+	if len(c.Files) > 0 {
+		files := make([]FileOptions, len(c.Files))
+		for i, file := range c.Files {
+			files[i] = n.file(fmt.Sprintf("files[%d]", i), file)
+		}
+		c.Files = files
+	}
+	c.Audit.File = n.file("audit.file", c.Audit.File)
+	c.Tenants.File = n.file("tenants.file", c.Tenants.File)
+
+	return c, n.warnings
+}
+
+// normalizer collects the warnings of Normalize
+type normalizer struct {
+	warnings []Warning
+}
+
+func (n *normalizer) replace(value *string, option, applied, reason string) {
+	n.warnings = append(n.warnings, Warning{Option: option, Value: *value, Applied: applied, Reason: reason})
+	*value = applied
+}
+
+// oneOf fills an empty value with def and replaces an invalid one with it
+func (n *normalizer) oneOf(value *string, option, def string, valid map[string]bool) {
+	switch {
+	case *value == "":
+		*value = def
+	case !valid[*value]:
+		n.replace(value, option, def, "unknown value")
+	}
+}
+
+// level replaces an invalid level with empty, the default of its stage
+func (n *normalizer) level(value *string, option string) {
+	if *value == "" {
+		return
+	}
+	if _, err := ParseLevel(*value); err != nil {
+		n.replace(value, option, "", "unknown level")
+	}
+}
+
+// encoding replaces an invalid encoding of an output with def
+func (n *normalizer) encoding(value *string, option, def string) {
+	if *value != "" && !validEncodings[*value] {
+		n.replace(value, option, def, "unknown encoding")
+	}
+}
+
+// sampling normalizes the sampling options, which are not applied unless
+// enabled
+func (n *normalizer) sampling(o SamplingOptions) SamplingOptions {
+	if !o.Enabled {
+		return o
+	}
+	if o.Tick <= 0 {
+		if o.Tick < 0 {
+			n.warnings = append(n.warnings, Warning{Option: "sampling.tick", Value: o.Tick.String(), Applied: time.Second.String(), Reason: "negative tick"})
+		}
+		o.Tick = time.Second
+	}
+	o.Initial, o.Thereafter = n.rate("sampling", o.Initial, o.Thereafter)
+	if len(o.Levels) > 0 {
+		levels := make(map[string]SamplingRate, len(o.Levels))
+		for _, level := range slices.Sorted(maps.Keys(o.Levels)) {
+			option := fmt.Sprintf("sampling.levels[%s]", level)
+			if _, err := zapcore.ParseLevel(level); err != nil {
+				n.warnings = append(n.warnings, Warning{Option: option, Value: level, Reason: "unknown level, the rate is ignored"})
+				continue
+			}
+			rate := o.Levels[level]
+			rate.Initial, rate.Thereafter = n.rate(option, rate.Initial, rate.Thereafter)
+			levels[level] = rate
+		}
+		o.Levels = levels
+	}
+	if o.UnsampledLevel != "" {
+		if _, err := zapcore.ParseLevel(o.UnsampledLevel); err != nil {
+			n.replace(&o.UnsampledLevel, "sampling.unsampled_level", "", "unknown level")
+		}
+	}
+	return o
+}
+
+// rate normalizes a sampling rate: negative counts are zero, and a rate
+// dropping every entry gets the default rate of 100 and every 100th
+func (n *normalizer) rate(option string, initial, thereafter int) (int, int) {
+	if initial < 0 {
+		n.warnings = append(n.warnings, Warning{Option: option + ".initial", Value: strconv.Itoa(initial), Applied: "0", Reason: "negative count"})
+		initial = 0
+	}
+	if thereafter < 0 {
+		n.warnings = append(n.warnings, Warning{Option: option + ".thereafter", Value: strconv.Itoa(thereafter), Applied: "0", Reason: "negative count"})
+		thereafter = 0
+	}
+	if initial == 0 && thereafter == 0 {
+		n.warnings = append(n.warnings, Warning{Option: option, Value: "0/0", Applied: "100/100", Reason: "initial and thereafter 0 would drop every entry"})
+		return 100, 100
+	}
+	return initial, thereafter
+}
+
+// file normalizes the options of a log file
+func (n *normalizer) file(option string, o FileOptions) FileOptions {
+	// Unknown policies drop entries, unknown algorithms do not compress
+	o.DiskFullPolicy = strings.ToLower(o.DiskFullPolicy)
+	if !validDiskFullPolicies[o.DiskFullPolicy] {
+		n.replace(&o.DiskFullPolicy, option+".disk_full_policy", DiskFullDrop, "unknown policy")
+	}
+	o.Compression = strings.ToLower(o.Compression)
+	if !validCompressions[o.Compression] {
+		n.replace(&o.Compression, option+".compression", CompressionNone, "unknown algorithm")
+	}
+
+	switch {
+	case o.RotationMode == "":
+		o.RotationMode = RotationModeSize
+	case !validRotationModes[o.RotationMode]:
+		n.warnings = append(n.warnings, Warning{
+			Option:  option + ".rotation_mode",
+			Value:   string(o.RotationMode),
+			Applied: string(RotationModeSize),
+			Reason:  "unknown rotation mode",
+		})
+		o.RotationMode = RotationModeSize
+	}
+	if (o.RotationMode == RotationModeTime || o.RotationMode == RotationModeBoth) && o.TimeRotationInterval == "" {
+		o.TimeRotationInterval = RotationDaily
+	}
+	return o
+}
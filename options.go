@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Option changes a logger derived with WithOptions
+type Option struct {
+	zap zap.Option
+}
+
+// AddCallerSkip skips skip more stack frames when annotating entries with
+// their caller, for wrappers that log on behalf of their own callers
+func AddCallerSkip(skip int) Option {
+	return Option{zap: zap.AddCallerSkip(skip)}
+}
+
+// IncreaseLevel drops the entries below level on the derived logger. It
+// cannot enable levels the parent logger drops; an invalid level is
+// ignored.
+func IncreaseLevel(level string) Option {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return Option{}
+	}
+	return Option{zap: zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &minLevelCore{Core: core, rank: rankOf(lvl)}
+	})}
+}
+
+// AddHooks runs hooks on the entries of the derived logger, before the
+// hooks and redaction of its parent
+func AddHooks(hooks ...Hook) Option {
+	if len(hooks) == 0 {
+		return Option{}
+	}
+	return Option{zap: zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return newHookCore(core, hooks)
+	})}
+}
+
+// OptionsLogger is an optional extension of Logger, implemented by
+// *ZapLogger, deriving loggers with options. ApplyOptions uses it when the
+// logger implements it.
+type OptionsLogger interface {
+	WithOptions(opts ...Option) Logger
+}
+
+// ApplyOptions returns l with the options applied if it implements
+// OptionsLogger, or l unchanged otherwise
+func ApplyOptions(l Logger, opts ...Option) Logger {
+	if ol, ok := l.(OptionsLogger); ok {
+		return ol.WithOptions(opts...)
+	}
+	return l
+}
+
+// WithOptions returns a copy of the logger with the options applied,
+// sharing its outputs and named levels
+func (l *ZapLogger) WithOptions(opts ...Option) Logger {
+	zapOpts := make([]zap.Option, 0, len(opts))
+	for _, opt := range opts {
+		if opt.zap != nil {
+			zapOpts = append(zapOpts, opt.zap)
+		}
+	}
+	return l.clone(l.logger.WithOptions(zapOpts...))
+}
+
+// minLevelCore drops the entries below a level rank. It compares ranks so
+// that custom levels are ordered by their severity.
+type minLevelCore struct {
+	zapcore.Core
+	rank int
+}
+
+func (c *minLevelCore) Enabled(l zapcore.Level) bool {
+	return rankOf(l) >= c.rank && c.Core.Enabled(l)
+}
+
+func (c *minLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &minLevelCore{Core: c.Core.With(fields), rank: c.rank}
+}
+
+func (c *minLevelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if rankOf(ent.Level) < c.rank {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
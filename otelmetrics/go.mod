@@ -0,0 +1,8 @@
+module github.com/csmart-libs/go-logger/otelmetrics
+
+go 1.24.4
+
+require (
+	go.opentelemetry.io/otel v1.36.0
+	go.opentelemetry.io/otel/metric v1.36.0
+)
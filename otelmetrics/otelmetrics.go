@@ -0,0 +1,91 @@
+// Package otelmetrics records logger metrics with OpenTelemetry instruments.
+//
+//	recorder, err := otelmetrics.New(otel.GetMeterProvider())
+//	if err != nil {
+//		return err
+//	}
+//	config := logger.ProductionConfig().WithMetrics(recorder)
+package otelmetrics
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ScopeName is the instrumentation scope of the meter
+const ScopeName = "github.com/csmart-libs/go-logger"
+
+// Recorder implements logger.MetricsRecorder with OpenTelemetry instruments:
+//
+//   - log.entries{level, logger}: counter of written entries
+//   - log.dropped{reason}: counter of dropped entries
+//   - log.write.duration{sink}: histogram of sink write durations in seconds
+//   - log.write.bytes{sink}: counter of bytes written
+//   - log.write.errors{sink}: counter of failed writes
+type Recorder struct {
+	entries       metric.Int64Counter
+	dropped       metric.Int64Counter
+	writeDuration metric.Float64Histogram
+	writeBytes    metric.Int64Counter
+	writeErrors   metric.Int64Counter
+}
+
+// New creates a Recorder with instruments from the provider's meter
+func New(provider metric.MeterProvider) (*Recorder, error) {
+	meter := provider.Meter(ScopeName)
+	r := &Recorder{}
+	var err error
+	if r.entries, err = meter.Int64Counter("log.entries",
+		metric.WithDescription("Number of log entries written, by level and logger name."),
+		metric.WithUnit("{entry}")); err != nil {
+		return nil, err
+	}
+	if r.dropped, err = meter.Int64Counter("log.dropped",
+		metric.WithDescription("Number of log entries dropped, by reason."),
+		metric.WithUnit("{entry}")); err != nil {
+		return nil, err
+	}
+	if r.writeDuration, err = meter.Float64Histogram("log.write.duration",
+		metric.WithDescription("Duration of writes to log sinks."),
+		metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if r.writeBytes, err = meter.Int64Counter("log.write.bytes",
+		metric.WithDescription("Number of bytes written to log sinks."),
+		metric.WithUnit("By")); err != nil {
+		return nil, err
+	}
+	if r.writeErrors, err = meter.Int64Counter("log.write.errors",
+		metric.WithDescription("Number of failed writes to log sinks."),
+		metric.WithUnit("{write}")); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// RecordEntry counts a written entry
+func (r *Recorder) RecordEntry(level, logger string) {
+	r.entries.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("level", level),
+		attribute.String("logger", logger),
+	))
+}
+
+// RecordWrite records the duration and size of a write to a sink
+func (r *Recorder) RecordWrite(sink string, bytes int, duration time.Duration, err error) {
+	ctx := context.Background()
+	attrs := metric.WithAttributes(attribute.String("sink", sink))
+	r.writeDuration.Record(ctx, duration.Seconds(), attrs)
+	r.writeBytes.Add(ctx, int64(bytes), attrs)
+	if err != nil {
+		r.writeErrors.Add(ctx, 1, attrs)
+	}
+}
+
+// RecordDropped counts dropped entries
+func (r *Recorder) RecordDropped(reason string, n int) {
+	r.dropped.Add(context.Background(), int64(n), metric.WithAttributes(attribute.String("reason", reason)))
+}
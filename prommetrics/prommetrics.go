@@ -0,0 +1,78 @@
+// Package prommetrics exposes logger metrics as Prometheus counters.
+//
+//	recorder, err := prommetrics.New(prometheus.DefaultRegisterer, "myapp")
+//	if err != nil {
+//		return err
+//	}
+//	config := logger.ProductionConfig().WithMetrics(recorder)
+package prommetrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Recorder implements logger.MetricsRecorder with Prometheus counters:
+//
+//   - log_entries_total{level, logger}
+//   - log_bytes_written_total{sink}
+//   - log_write_errors_total{sink}
+//   - log_dropped_entries_total{reason}
+type Recorder struct {
+	entries     *prometheus.CounterVec
+	bytes       *prometheus.CounterVec
+	writeErrors *prometheus.CounterVec
+	dropped     *prometheus.CounterVec
+}
+
+// New creates a Recorder and registers its counters with reg. The namespace
+// is prefixed to the metric names and may be empty.
+func New(reg prometheus.Registerer, namespace string) (*Recorder, error) {
+	r := &Recorder{
+		entries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "log_entries_total",
+			Help:      "Number of log entries written, by level and logger name.",
+		}, []string{"level", "logger"}),
+		bytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "log_bytes_written_total",
+			Help:      "Number of bytes written to log sinks.",
+		}, []string{"sink"}),
+		writeErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "log_write_errors_total",
+			Help:      "Number of failed writes to log sinks.",
+		}, []string{"sink"}),
+		dropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "log_dropped_entries_total",
+			Help:      "Number of log entries dropped, by reason.",
+		}, []string{"reason"}),
+	}
+	for _, c := range []prometheus.Collector{r.entries, r.bytes, r.writeErrors, r.dropped} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// RecordEntry counts a written entry
+func (r *Recorder) RecordEntry(level, logger string) {
+	r.entries.WithLabelValues(level, logger).Inc()
+}
+
+// RecordWrite counts the bytes written to a sink and failed writes
+func (r *Recorder) RecordWrite(sink string, bytes int, _ time.Duration, err error) {
+	r.bytes.WithLabelValues(sink).Add(float64(bytes))
+	if err != nil {
+		r.writeErrors.WithLabelValues(sink).Inc()
+	}
+}
+
+// RecordDropped counts dropped entries
+func (r *Recorder) RecordDropped(reason string, n int) {
+	r.dropped.WithLabelValues(reason).Add(float64(n))
+}
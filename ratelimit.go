@@ -0,0 +1,249 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// DefaultRateLimitReportInterval is how often a key that keeps being
+	// throttled reports the number of suppressed entries
+	DefaultRateLimitReportInterval = 10 * time.Second
+
+	// maxRateLimitKeys bounds the number of tracked keys. Idle keys are
+	// evicted once it is reached; while it stays reached, new keys share
+	// one overflow bucket.
+	maxRateLimitKeys = 10000
+
+	// rateLimitEvictInterval is the shortest time between two evictions
+	rateLimitEvictInterval = time.Second
+)
+
+// tokenBucket tracks the budget of one rate limiting key
+type tokenBucket struct {
+	tokens     float64
+	last       time.Time
+	suppressed int
+	since      time.Time
+}
+
+// rateLimiter holds one token bucket per key. Buckets refill at rate tokens
+// per second up to burst; each entry spends one token.
+type rateLimiter struct {
+	mu       sync.Mutex
+	rate     float64
+	burst    float64
+	interval time.Duration
+	buckets  map[string]*tokenBucket
+
+	// overflow is shared by the keys arriving while buckets is full
+	overflow *tokenBucket
+
+	// evicted is the time of the last eviction
+	evicted time.Time
+}
+
+func newRateLimiter(options RateLimitOptions) *rateLimiter {
+	burst := float64(options.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	interval := options.ReportInterval
+	if interval <= 0 {
+		interval = DefaultRateLimitReportInterval
+	}
+	return &rateLimiter{
+		rate:     options.Rate,
+		burst:    burst,
+		interval: interval,
+		buckets:  make(map[string]*tokenBucket),
+	}
+}
+
+// allow spends a token of key. It also returns the number of suppressed
+// entries to report, which is non-zero when the key is allowed again after
+// being throttled or has been throttled for a whole report interval.
+func (l *rateLimiter) allow(key string, now time.Time) (bool, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= maxRateLimitKeys && now.Sub(l.evicted) >= rateLimitEvictInterval {
+			l.evict(now)
+		}
+		switch {
+		case len(l.buckets) < maxRateLimitKeys:
+			b = &tokenBucket{tokens: l.burst, last: now}
+			l.buckets[key] = b
+		case l.overflow == nil:
+			l.overflow = &tokenBucket{tokens: l.burst, last: now}
+			b = l.overflow
+		default:
+			b = l.overflow
+		}
+	}
+	l.refill(b, now)
+
+	if b.tokens >= 1 {
+		b.tokens--
+		suppressed := b.suppressed
+		b.suppressed = 0
+		return true, suppressed
+	}
+
+	if b.suppressed == 0 {
+		b.since = now
+	}
+	b.suppressed++
+	if now.Sub(b.since) < l.interval {
+		return false, 0
+	}
+	suppressed := b.suppressed
+	b.suppressed = 0
+	return false, suppressed
+}
+
+func (l *rateLimiter) refill(b *tokenBucket, now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = min(l.burst, b.tokens+elapsed*l.rate)
+		b.last = now
+	}
+}
+
+// evict drops the buckets of keys that are back to a full budget
+func (l *rateLimiter) evict(now time.Time) {
+	l.evicted = now
+	for key, b := range l.buckets {
+		l.refill(b, now)
+		if b.suppressed == 0 && b.tokens >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// rateLimitCore throttles entries sharing a message, or the value of a key
+// field, and reports how many entries were suppressed
+type rateLimitCore struct {
+	zapcore.Core
+	limiter  *rateLimiter
+	keyField string
+	metrics  MetricsRecorder
+
+	// unlimited is the rank from which entries are never throttled
+	unlimited int
+
+	// contextKey is the value of keyField added through With, if any
+	contextKey string
+	hasContext bool
+}
+
+func newRateLimitCore(core zapcore.Core, options RateLimitOptions, metrics MetricsRecorder) zapcore.Core {
+	unlimited := zapcore.ErrorLevel
+	if options.UnlimitedLevel != "" {
+		if level, err := ParseLevel(options.UnlimitedLevel); err == nil {
+			unlimited = level
+		}
+	}
+	return &rateLimitCore{
+		Core:      core,
+		limiter:   newRateLimiter(options),
+		keyField:  options.KeyField,
+		metrics:   metrics,
+		unlimited: rankOf(unlimited),
+	}
+}
+
+func (c *rateLimitCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	if key, ok := c.fieldKey(fields); ok {
+		clone.contextKey, clone.hasContext = key, true
+	}
+	return &clone
+}
+
+func (c *rateLimitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if rankOf(ent.Level) >= c.unlimited {
+		return c.Core.Check(ent, ce)
+	}
+	inner := c.Core.Check(ent, nil)
+	if inner == nil {
+		return ce
+	}
+	return ce.AddCore(inner.Entry, &rateLimitWriter{core: c, checked: inner})
+}
+
+// fieldKey returns the value of the key field among fields
+func (c *rateLimitCore) fieldKey(fields []zapcore.Field) (string, bool) {
+	if c.keyField == "" {
+		return "", false
+	}
+	return fieldValue(fields, c.keyField)
+}
+
+// key returns the rate limiting key of an entry. Entries without the key
+// field are keyed by message.
+func (c *rateLimitCore) key(ent zapcore.Entry, fields []zapcore.Field) string {
+	if key, ok := c.fieldKey(fields); ok {
+		return key
+	}
+	if c.hasContext {
+		return c.contextKey
+	}
+	return ent.Message
+}
+
+// report writes a notice of suppressed entries at the level of the entry
+// that triggered it
+func (c *rateLimitCore) report(ent zapcore.Entry, key string, suppressed int) error {
+	notice := zapcore.Entry{
+		Level:      ent.Level,
+		Time:       ent.Time,
+		LoggerName: ent.LoggerName,
+		Message:    fmt.Sprintf("suppressed %d entries", suppressed),
+	}
+	checked := c.Core.Check(notice, nil)
+	if checked == nil {
+		return nil
+	}
+	return writeChecked(checked, notice, []zapcore.Field{
+		{Key: "rate_limit_key", Type: zapcore.StringType, String: key},
+		{Key: "suppressed", Type: zapcore.Int64Type, Integer: int64(suppressed)},
+	})
+}
+
+// rateLimitWriter is a single-use core that spends a token before writing an
+// entry through the CheckedEntry of the wrapped core
+type rateLimitWriter struct {
+	core    *rateLimitCore
+	checked *zapcore.CheckedEntry
+}
+
+func (w *rateLimitWriter) Enabled(zapcore.Level) bool { return true }
+
+func (w *rateLimitWriter) With([]zapcore.Field) zapcore.Core { return w }
+
+func (w *rateLimitWriter) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, w)
+}
+
+func (w *rateLimitWriter) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	key := w.core.key(ent, fields)
+	allowed, suppressed := w.core.limiter.allow(key, ent.Time)
+	var err error
+	if suppressed > 0 {
+		err = w.core.report(ent, key, suppressed)
+	}
+	if !allowed {
+		w.core.metrics.RecordDropped(DropReasonRateLimited, 1)
+		return err
+	}
+	return errors.Join(err, writeChecked(w.checked, ent, fields))
+}
+
+func (w *rateLimitWriter) Sync() error { return nil }
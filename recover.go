@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"go.uber.org/zap"
+)
+
+// Recover recovers from a panic and logs the recovered value and the stack
+// at error level with the logger of ctx (see FromContext), its context
+// fields and the given fields. It must be deferred directly, typically at
+// the top of goroutines that have no recovering middleware:
+//
+//	go func() {
+//		defer logger.Recover(ctx, logger.String("job", job.ID))
+//		job.Run(ctx)
+//	}()
+//
+// With Config.RepanicOnRecover, it panics again with the recovered value
+// after logging it.
+func Recover(ctx context.Context, fields ...zap.Field) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+	err, ok := recovered.(error)
+	if !ok {
+		err = fmt.Errorf("%v", recovered)
+	}
+	l := FromContext(ctx)
+	fields = append(fields[:len(fields):len(fields)], Err(err), String("stack", string(debug.Stack())))
+	if cl, ok := l.(ContextLogger); ok {
+		cl.ErrorContext(ctx, "panic recovered", fields...)
+	} else {
+		l.Error("panic recovered", withContextFields(ctx, fields)...)
+	}
+	if repanicOnRecover(l) {
+		panic(recovered)
+	}
+}
+
+// repanicOnRecover reports whether Recover panics again after logging with
+// l. Loggers that do not tell, such as wrappers, follow the global logger.
+func repanicOnRecover(l Logger) bool {
+	type repanicker interface{ RepanicOnRecover() bool }
+	if r, ok := l.(repanicker); ok {
+		return r.RepanicOnRecover()
+	}
+	if r, ok := GetLogger().(repanicker); ok {
+		return r.RepanicOnRecover()
+	}
+	return false
+}
+
+// RepanicOnRecover reports whether Recover panics again after logging with
+// the logger, per Config.RepanicOnRecover. Wrappers of a *ZapLogger can
+// implement it to keep the setting.
+func (l *ZapLogger) RepanicOnRecover() bool {
+	return l.repanic
+}
@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultRedactKeys are the field names masked when redaction is enabled
+// without an explicit key list
+var DefaultRedactKeys = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"access_token",
+	"refresh_token",
+	"api_key",
+	"apikey",
+	"authorization",
+	"cookie",
+	"ssn",
+	"credit_card",
+}
+
+// DefaultRedactMask replaces redacted values when no mask is configured
+const DefaultRedactMask = "***"
+
+// redactor masks the values of sensitive fields by key
+type redactor struct {
+	keys map[string]struct{}
+	mask string
+}
+
+func newRedactor(options RedactionOptions) *redactor {
+	keys := options.Keys
+	if len(keys) == 0 {
+		keys = DefaultRedactKeys
+	}
+	r := &redactor{keys: make(map[string]struct{}, len(keys)), mask: options.Mask}
+	for _, key := range keys {
+		r.keys[strings.ToLower(key)] = struct{}{}
+	}
+	if r.mask == "" {
+		r.mask = DefaultRedactMask
+	}
+	return r
+}
+
+// sensitive reports whether a field key is configured for redaction
+func (r *redactor) sensitive(key string) bool {
+	_, ok := r.keys[strings.ToLower(key)]
+	return ok
+}
+
+// redact returns fields with sensitive values masked
+func (r *redactor) redact(fields []zapcore.Field) []zapcore.Field {
+	out, _ := r.redactFields(fields)
+	return out
+}
+
+// redactFields masks sensitive values and reports whether anything changed.
+// The input slice is never modified; a copy is made only when needed.
+func (r *redactor) redactFields(fields []zapcore.Field) ([]zapcore.Field, bool) {
+	var out []zapcore.Field
+	for i, f := range fields {
+		masked, changed := r.redactField(f)
+		if !changed {
+			if out != nil {
+				out = append(out, f)
+			}
+			continue
+		}
+		if out == nil {
+			out = make([]zapcore.Field, i, len(fields))
+			copy(out, fields[:i])
+		}
+		out = append(out, masked)
+	}
+	if out == nil {
+		return fields, false
+	}
+	return out, true
+}
+
+func (r *redactor) redactField(f zapcore.Field) (zapcore.Field, bool) {
+	if key := fieldKey(f); key != "" && f.Type != zapcore.NamespaceType && r.sensitive(key) {
+		return zap.String(key, r.mask), true
+	}
+	// Structs logged through Any mask their tagged fields with the mask
+	if masked, changed := withTagMask(f, r.mask); changed {
+		return masked, true
+	}
+	// Descend into objects built with Dict
+	if dict, ok := f.Interface.(fieldObject); ok && f.Type == zapcore.ObjectMarshalerType {
+		if redacted, changed := r.redactFields(dict); changed {
+			return zap.Object(f.Key, fieldObject(redacted)), true
+		}
+	}
+	return f, false
+}
+
+// newRedactionCore masks sensitive fields added through With and per call
+func newRedactionCore(core zapcore.Core, options RedactionOptions) zapcore.Core {
+	r := newRedactor(options)
+	return newTransformCore(core, &entryTransform{
+		with: r.redact,
+		write: func(_ *zapcore.Entry, fields []zapcore.Field) ([]zapcore.Field, bool) {
+			return r.redact(fields), true
+		},
+	})
+}
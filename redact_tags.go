@@ -0,0 +1,378 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Struct tag values honored by Any, also in the elements of slices, arrays
+// and maps of structs:
+//
+//	Password string `log:"-"`    // omitted from the output
+//	Email    string `log:"mask"` // written as "***"
+const (
+	logTagOmit = "-"
+	logTagMask = "mask"
+)
+
+type tagAction int
+
+const (
+	tagPlain tagAction = iota
+	tagOmit
+	tagMask
+	tagNested
+
+	// tagElems marks a slice, array or map of tagged structs
+	tagElems
+)
+
+// taggedField describes how one struct field is logged
+type taggedField struct {
+	// index is the index sequence of the field, through embedded structs
+	index  []int
+	name   string
+	action tagAction
+}
+
+// taggedStructs caches the field plan per struct type; nil means the type has
+// no log tags anywhere and can be reflected as is
+var taggedStructs sync.Map // reflect.Type -> []taggedField
+
+// taggedStructFields returns the field plan of a struct type, or nil if
+// neither it nor any nested struct uses log tags
+func taggedStructFields(t reflect.Type) []taggedField {
+	if cached, ok := taggedStructs.Load(t); ok {
+		return cached.([]taggedField)
+	}
+	fields := planStructFields(t, map[reflect.Type]bool{})
+	taggedStructs.Store(t, fields)
+	return fields
+}
+
+// planStructFields computes the field plan of t. Types already being planned
+// further up (recursive types) are conservatively treated as tagged.
+func planStructFields(t reflect.Type, visiting map[reflect.Type]bool) []taggedField {
+	fields, tagged := structFields(t, visiting)
+	if !tagged {
+		return nil
+	}
+	return fields
+}
+
+// structFields computes the plan of every field of t and reports whether
+// any of them is tagged. The fields of embedded structs are flattened like
+// encoding/json does, fields declared in t taking precedence.
+func structFields(t reflect.Type, visiting map[reflect.Type]bool) ([]taggedField, bool) {
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	var fields []taggedField
+	tagged := false
+	declared := map[string]bool{}
+	var embedded []int
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		jsonName, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if jsonName == "-" {
+			continue
+		}
+		if sf.Anonymous && jsonName == "" && sf.Tag.Get("log") == "" {
+			if st := structType(sf.Type); st != nil && !visiting[st] {
+				embedded = append(embedded, len(fields))
+				fields = append(fields, taggedField{index: []int{i}})
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+
+		name := sf.Name
+		if jsonName != "" {
+			name = jsonName
+		}
+		declared[name] = true
+
+		field := taggedField{index: []int{i}, name: name}
+		switch sf.Tag.Get("log") {
+		case logTagOmit:
+			field.action = tagOmit
+			tagged = true
+		case logTagMask:
+			field.action = tagMask
+			tagged = true
+		default:
+			if st := structType(sf.Type); st != nil {
+				use, known := taggedType(st, visiting)
+				if use {
+					field.action = tagNested
+				}
+				tagged = tagged || known
+			} else if st := elemStructType(sf.Type); st != nil {
+				use, known := taggedType(st, visiting)
+				if use {
+					field.action = tagElems
+				}
+				tagged = tagged || known
+			}
+		}
+		fields = append(fields, field)
+	}
+	if len(embedded) == 0 {
+		return fields, tagged
+	}
+
+	// Replace each embedded struct with its fields
+	flat := make([]taggedField, 0, len(fields))
+	next := 0
+	for i, field := range fields {
+		if next < len(embedded) && embedded[next] == i {
+			next++
+			inner, innerTagged := structFields(structType(t.Field(field.index[0]).Type), visiting)
+			tagged = tagged || innerTagged
+			for _, f := range inner {
+				if declared[f.name] {
+					continue
+				}
+				f.index = append([]int{field.index[0]}, f.index...)
+				flat = append(flat, f)
+			}
+			continue
+		}
+		flat = append(flat, field)
+	}
+	return flat, tagged
+}
+
+// taggedType reports whether values of the struct type st must be written
+// through its field plan, and whether st is known to use log tags. Types
+// being planned further up are conservatively written through their plan.
+func taggedType(st reflect.Type, visiting map[reflect.Type]bool) (use, tagged bool) {
+	if visiting[st] {
+		return true, false
+	}
+	if cached, ok := taggedStructs.Load(st); ok {
+		tagged = cached.([]taggedField) != nil
+		return tagged, tagged
+	}
+	tagged = planStructFields(st, visiting) != nil
+	return tagged, tagged
+}
+
+// elemStructType returns the struct type behind the elements of a slice,
+// array or map type t, dereferencing one pointer
+func elemStructType(t reflect.Type) reflect.Type {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return structType(t.Elem())
+	}
+	return nil
+}
+
+// structType returns the struct type behind t, dereferencing one pointer
+func structType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct {
+		return t
+	}
+	return nil
+}
+
+// taggedStruct marshals a struct value honoring its log tags
+type taggedStruct struct {
+	value  reflect.Value
+	fields []taggedField
+
+	// mask replaces the values of masked fields; empty means DefaultRedactMask
+	mask string
+}
+
+func (s taggedStruct) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	mask := s.mask
+	if mask == "" {
+		mask = DefaultRedactMask
+	}
+	for _, f := range s.fields {
+		// Fields of embedded structs behind a nil pointer are left out
+		fv, err := s.value.FieldByIndexErr(f.index)
+		if err != nil {
+			continue
+		}
+		switch f.action {
+		case tagOmit:
+			continue
+		case tagMask:
+			enc.AddString(f.name, mask)
+		case tagNested:
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					enc.AddReflected(f.name, nil)
+					continue
+				}
+				fv = fv.Elem()
+			}
+			nested := taggedStructFields(fv.Type())
+			if nested == nil {
+				if err := enc.AddReflected(f.name, fv.Interface()); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := enc.AddObject(f.name, taggedStruct{value: fv, fields: nested, mask: s.mask}); err != nil {
+				return err
+			}
+		case tagElems:
+			if err := addTaggedElems(enc, f.name, fv, s.mask); err != nil {
+				return err
+			}
+		default:
+			if err := enc.AddReflected(f.name, fv.Interface()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addTaggedElems adds a slice, array or map of structs, honoring the log
+// tags of its elements
+func addTaggedElems(enc zapcore.ObjectEncoder, key string, v reflect.Value, mask string) error {
+	fields := taggedStructFields(elemStructType(v.Type()))
+	if fields == nil || v.Kind() != reflect.Array && v.IsNil() {
+		return enc.AddReflected(key, v.Interface())
+	}
+	if v.Kind() == reflect.Map {
+		return enc.AddObject(key, taggedMap{value: v, fields: fields, mask: mask})
+	}
+	return enc.AddArray(key, taggedArray{value: v, fields: fields, mask: mask})
+}
+
+// taggedArray marshals a slice or array of structs honoring their log tags
+type taggedArray struct {
+	value  reflect.Value
+	fields []taggedField
+	mask   string
+}
+
+func (a taggedArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for i := 0; i < a.value.Len(); i++ {
+		elem := a.value.Index(i)
+		if elem.Kind() == reflect.Pointer {
+			if elem.IsNil() {
+				if err := enc.AppendReflected(nil); err != nil {
+					return err
+				}
+				continue
+			}
+			elem = elem.Elem()
+		}
+		if err := enc.AppendObject(taggedStruct{value: elem, fields: a.fields, mask: a.mask}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// taggedMap marshals a map of structs honoring their log tags, in key order
+type taggedMap struct {
+	value  reflect.Value
+	fields []taggedField
+	mask   string
+}
+
+func (m taggedMap) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := m.value.MapKeys()
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = fmt.Sprint(key.Interface())
+	}
+	order := make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return names[order[i]] < names[order[j]] })
+
+	for _, i := range order {
+		elem := m.value.MapIndex(keys[i])
+		if elem.Kind() == reflect.Pointer {
+			if elem.IsNil() {
+				if err := enc.AddReflected(names[i], nil); err != nil {
+					return err
+				}
+				continue
+			}
+			elem = elem.Elem()
+		}
+		if err := enc.AddObject(names[i], taggedStruct{value: elem, fields: m.fields, mask: m.mask}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withTagMask returns a field created by Any for a tagged value with its
+// masked fields written as mask, and whether the field changed
+func withTagMask(f zapcore.Field, mask string) (zapcore.Field, bool) {
+	switch v := f.Interface.(type) {
+	case taggedStruct:
+		if f.Type == zapcore.ObjectMarshalerType && v.mask != mask {
+			v.mask = mask
+			return zap.Object(f.Key, v), true
+		}
+	case taggedMap:
+		if f.Type == zapcore.ObjectMarshalerType && v.mask != mask {
+			v.mask = mask
+			return zap.Object(f.Key, v), true
+		}
+	case taggedArray:
+		if f.Type == zapcore.ArrayMarshalerType && v.mask != mask {
+			v.mask = mask
+			return zap.Array(f.Key, v), true
+		}
+	}
+	return f, false
+}
+
+// taggedStructField returns an object field for structs using log tags,
+// or an array or object field for slices, arrays and maps of them
+func taggedStructField(key string, val any) (zap.Field, bool) {
+	v := reflect.ValueOf(val)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return zap.Field{}, false
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		fields := taggedStructFields(v.Type())
+		if fields == nil {
+			return zap.Field{}, false
+		}
+		return zap.Object(key, taggedStruct{value: v, fields: fields}), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		st := elemStructType(v.Type())
+		if st == nil || v.Kind() != reflect.Array && v.IsNil() {
+			return zap.Field{}, false
+		}
+		fields := taggedStructFields(st)
+		if fields == nil {
+			return zap.Field{}, false
+		}
+		if v.Kind() == reflect.Map {
+			return zap.Object(key, taggedMap{value: v, fields: fields}), true
+		}
+		return zap.Array(key, taggedArray{value: v, fields: fields}), true
+	}
+	return zap.Field{}, false
+}
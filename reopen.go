@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// reopenableFile is an append-only file that can be closed and opened again
+// at the same path, for files rotated by external tools
+type reopenableFile struct {
+	path string
+	mode os.FileMode
+
+	mu sync.Mutex
+	f  *os.File
+}
+
+func openReopenableFile(path string, mode os.FileMode) (*reopenableFile, error) {
+	if mode == 0 {
+		mode = 0644
+	}
+	r := &reopenableFile{path: path, mode: mode}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// open opens the file; r.mu must be held or r not yet shared
+func (r *reopenableFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, r.mode)
+	if err != nil {
+		return err
+	}
+	r.f = f
+	return nil
+}
+
+func (r *reopenableFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.f == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	return r.f.Write(p)
+}
+
+func (r *reopenableFile) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.f == nil {
+		return nil
+	}
+	return r.f.Sync()
+}
+
+// Close closes the file; the next write opens it again
+func (r *reopenableFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
+
+// reopenFunc returns the func closing a file writer so that its next write
+// opens the file at its path again, or nil if it cannot
+func reopenFunc(file io.Writer) func() error {
+	switch f := file.(type) {
+	case *TimeRotatingWriter:
+		return f.reopen
+	case *lumberjack.Logger:
+		return f.Close
+	case *reopenableFile:
+		return f.Close
+	}
+	return nil
+}
+
+// watchExternalRotation checks the file of a file writer every
+// ReopenInterval, and reopens it if it was renamed or deleted, since the
+// writer would keep writing to the old file, or truncated, since the writer
+// would keep writing at its old offset or size. It returns the func
+// stopping the check, or nil if the writer cannot reopen its file.
+func watchExternalRotation(file io.Writer, options FileOptions) (stop func()) {
+	reopen := reopenFunc(file)
+	if reopen == nil {
+		return nil
+	}
+	filename := activeFilename(file, options)
+	ticker := time.NewTicker(options.ReopenInterval)
+	done := make(chan struct{})
+
+	go func() {
+		var last os.FileInfo
+		for {
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+			name := filename()
+			info, err := os.Stat(name)
+			if last != nil && (err != nil || !os.SameFile(info, last) || info.Size() < last.Size()) {
+				reopen()
+				// A write of nothing opens the file again
+				file.Write(nil)
+				info, err = os.Stat(name)
+			}
+			if err != nil {
+				info = nil
+			}
+			last = info
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
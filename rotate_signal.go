@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+)
+
+var errNoRotatingFile = errors.New("logger: no rotating file output")
+
+// rotateOnSignal calls rotate whenever one of the signals is received,
+// defaulting to defaultRotateSignals, passing its errors to report
+func rotateOnSignal(rotate func() error, report func(error), sigs []os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = defaultRotateSignals
+	}
+	if len(sigs) == 0 {
+		return func() {}
+	}
+
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sigs...)
+	go func() {
+		for {
+			select {
+			case sig := <-ch:
+				if err := rotate(); err != nil {
+					report(fmt.Errorf("logger: rotate on %v: %w", sig, err))
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
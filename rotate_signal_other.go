@@ -0,0 +1,9 @@
+//go:build !unix
+
+package logger
+
+import "os"
+
+// defaultRotateSignals are handled by RotateOnSignal when no signal is given.
+// SIGUSR1 does not exist on this platform.
+var defaultRotateSignals []os.Signal
@@ -0,0 +1,11 @@
+//go:build unix
+
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+// defaultRotateSignals are handled by RotateOnSignal when no signal is given
+var defaultRotateSignals = []os.Signal{syscall.SIGUSR1}
@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// newRotateHook returns the rotateFunc compressing and encrypting each
+// rotated file, then running FileOptions.OnRotate, OnRotateCommand and
+// Archive, or nil if there is nothing to do. It runs in the background so
+// that a slow compression, upload or command never blocks logging; rotated
+// files are processed one at a time, and the backups pending when the file
+// is opened are processed too. active returns the file currently written
+// to.
+func newRotateHook(options FileOptions, config Config, active func() string) (rotateFunc, error) {
+	recipients, err := parseRecipients(options.EncryptRecipients)
+	if err != nil {
+		return nil, err
+	}
+	algorithm := options.compression()
+	processing := algorithm != CompressionNone || len(recipients) > 0
+	if !processing && options.OnRotate == nil && len(options.OnRotateCommand) == 0 && options.Archive.Uploader == nil {
+		return nil, nil
+	}
+	report := config.internalErrorHandler()
+
+	// process compresses, then encrypts a rotated file, returning the path
+	// of the result
+	process := func(path string) string {
+		if algorithm != CompressionNone && !isCompressed(path) && !isEncrypted(path) {
+			compressed, err := compressFile(path, algorithm, options.CompressionLevel)
+			if err != nil {
+				report(fmt.Errorf("logger: compress %s: %w", path, err))
+				return path
+			}
+			path = compressed
+		}
+		if len(recipients) > 0 && !isEncrypted(path) {
+			encrypted, err := encryptFile(path, recipients)
+			if err != nil {
+				report(fmt.Errorf("logger: encrypt %s: %w", path, err))
+				return path
+			}
+			path = encrypted
+		}
+		return path
+	}
+	// catchUp processes the backups still pending, except the newest
+	// CompressDelay ones: those left by a crash or a restart, or by a
+	// rotation that was not detected
+	catchUp := func() {
+		if options.RotationMode == RotationModeNone {
+			return
+		}
+		backups := pendingBackups(options.Filename, active())
+		for i := 0; i < len(backups)-options.CompressDelay; i++ {
+			process(backups[i])
+		}
+	}
+	command := append([]string(nil), options.OnRotateCommand...)
+	var mu sync.Mutex
+	if processing {
+		go func() {
+			mu.Lock()
+			defer mu.Unlock()
+			catchUp()
+		}()
+	}
+
+	return func(oldPath string, at time.Time) {
+		if oldPath == "" {
+			return
+		}
+		go func() {
+			mu.Lock()
+			defer mu.Unlock()
+
+			path := oldPath
+			if processing {
+				if options.CompressDelay == 0 {
+					path = process(path)
+				}
+				catchUp()
+			}
+			if options.OnRotate != nil {
+				options.OnRotate(path)
+			}
+			if len(command) > 0 {
+				args := append(command[1:len(command):len(command)], path)
+				if out, err := exec.Command(command[0], args...).CombinedOutput(); err != nil {
+					report(fmt.Errorf("logger: on-rotate command for %s: %w: %s", path, err, strings.TrimSpace(string(out))))
+				}
+			}
+			if options.Archive.Uploader != nil {
+				if options.LocalTime {
+					at = at.Local()
+				} else {
+					at = at.UTC()
+				}
+				if err := archive(options.Archive, path, at); err != nil {
+					report(err)
+				}
+			}
+		}()
+	}, nil
+}
@@ -2,11 +2,15 @@ package logger
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"time"
 
+	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
@@ -18,12 +22,37 @@ type TimeRotatingWriter struct {
 	lastRotationTime  time.Time
 	mu                sync.Mutex
 	baseFilename      string
+	linked            string
+
+	// linkFailed is the active file the link could not be pointed to
+	linkFailed string
+
+	// offset is the RotationTime past midnight; periods are computed from
+	// the current time minus offset
+	offset time.Duration
+
+	// weekStart is the first day of the weeks of weekly rotation
+	weekStart time.Weekday
+
+	// timer rotates at the next interval boundary even without writes
+	timer  *time.Timer
+	closed bool
+
+	// onTimerRotate is called after a timer-driven rotation, without mu held
+	onTimerRotate func()
+
+	// report receives the failures to link the active file; nil uses the
+	// default internal error handler
+	report func(error)
+
+	// cleanupMu serializes the cleanup of previous periods
+	cleanupMu sync.Mutex
 }
 
 // NewTimeRotatingWriter creates a new time-based rotating writer
 func NewTimeRotatingWriter(options FileOptions) *TimeRotatingWriter {
 	// Extract base filename and extension
-	baseFilename := options.Filename
+	baseFilename := expandFilename(options.Filename)
 
 	// Set time format based on interval
 	timeFormat := options.TimeRotationFormat
@@ -34,7 +63,7 @@ func NewTimeRotatingWriter(options FileOptions) *TimeRotatingWriter {
 		case RotationDaily:
 			timeFormat = "2006-01-02"
 		case RotationWeekly:
-			timeFormat = "2006-W01"
+			timeFormat = "2006-01-02"
 		case RotationMonthly:
 			timeFormat = "2006-01"
 		default:
@@ -42,6 +71,13 @@ func NewTimeRotatingWriter(options FileOptions) *TimeRotatingWriter {
 		}
 	}
 
+	// Daily, weekly and monthly periods may start past midnight
+	var offset time.Duration
+	switch options.TimeRotationInterval {
+	case RotationDaily, RotationWeekly, RotationMonthly:
+		offset, _ = parseRotationTime(options.RotationTime)
+	}
+
 	// Create initial filename with timestamp
 	now := time.Now()
 	if options.LocalTime {
@@ -49,8 +85,10 @@ func NewTimeRotatingWriter(options FileOptions) *TimeRotatingWriter {
 	} else {
 		now = now.UTC()
 	}
+	now = now.Add(-offset)
+	weekStart, _ := parseWeekday(options.WeekStartDay)
 
-	timestampedFilename := generateTimestampedFilename(baseFilename, now, timeFormat)
+	timestampedFilename := generateTimestampedFilename(baseFilename, periodStart(now, options.TimeRotationInterval, weekStart), timeFormat)
 
 	lj := &lumberjack.Logger{
 		Filename:   timestampedFilename,
@@ -61,13 +99,175 @@ func NewTimeRotatingWriter(options FileOptions) *TimeRotatingWriter {
 		Compress:   options.Compress,
 	}
 
-	return &TimeRotatingWriter{
+	w := &TimeRotatingWriter{
 		Logger:            lj,
 		options:           options,
 		currentTimeFormat: timeFormat,
 		lastRotationTime:  now,
 		baseFilename:      baseFilename,
+		offset:            offset,
+		weekStart:         weekStart,
+	}
+	w.scheduleRotation(now)
+	w.cleanup()
+	return w
+}
+
+// cleanup applies MaxBackups and MaxAge across all periods; lumberjack only
+// sees the backups of the current period's file. Compression of the files
+// of previous periods is left to the rotation hook. It runs in the
+// background and must be called with mu held, or before the writer is
+// shared.
+func (w *TimeRotatingWriter) cleanup() {
+	if w.options.MaxBackups <= 0 && w.options.MaxAge <= 0 {
+		return
+	}
+	active := w.Logger.Filename
+	retention := &backupRetention{
+		filename:   w.baseFilename,
+		active:     func() string { return active },
+		maxBackups: w.options.MaxBackups,
+		maxAge:     time.Duration(w.options.MaxAge) * 24 * time.Hour,
+	}
+	go func() {
+		w.cleanupMu.Lock()
+		defer w.cleanupMu.Unlock()
+		retention.enforce()
+	}()
+}
+
+// now returns the current time in the writer's time zone, shifted back by
+// the rotation time so that periods start at midnight
+func (w *TimeRotatingWriter) now() time.Time {
+	if w.options.LocalTime {
+		return time.Now().Local().Add(-w.offset)
+	}
+	return time.Now().UTC().Add(-w.offset)
+}
+
+// periodStart returns the time naming the period holding now: the midnight
+// starting the week for weekly rotation, now itself otherwise
+func periodStart(now time.Time, interval TimeRotationInterval, weekStart time.Weekday) time.Time {
+	if interval != RotationWeekly {
+		return now
+	}
+	year, month, day := now.Date()
+	days := (int(now.Weekday()) - int(weekStart) + 7) % 7
+	return time.Date(year, month, day-days, 0, 0, 0, 0, now.Location())
+}
+
+// parseWeekday parses a day name such as "sunday". Empty is Monday.
+func parseWeekday(name string) (time.Weekday, error) {
+	if name == "" {
+		return time.Monday, nil
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(name, day.String()) {
+			return day, nil
+		}
+	}
+	return time.Monday, fmt.Errorf("logger: invalid week start day %q", name)
+}
+
+// parseRotationTime parses an "HH:MM" wall-clock time into its offset from
+// midnight. Empty is midnight.
+func parseRotationTime(clock string) (time.Duration, error) {
+	if clock == "" {
+		return 0, nil
+	}
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("logger: invalid rotation time %q, want HH:MM", clock)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// scheduleRotation arms the timer for the interval boundary after now, both
+// shifted by the rotation time
+func (w *TimeRotatingWriter) scheduleRotation(now time.Time) {
+	next, ok := nextRotationTime(now, w.options.TimeRotationInterval, w.weekStart)
+	if !ok {
+		return
+	}
+	w.timer = time.AfterFunc(next.Sub(now), w.rotateOnTimer)
+}
+
+// rotateOnTimer rotates at an interval boundary, so that an idle writer
+// does not keep the previous period's file open. The previous file is
+// synced to disk and the new one created right away.
+func (w *TimeRotatingWriter) rotateOnTimer() {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return
+	}
+	now := w.now()
+	rotated := false
+	if w.shouldRotateByTime(now) {
+		previous := w.Logger.Filename
+		syncFile(previous)
+		if w.rotateByTime(now) == nil {
+			rotated = true
+			// A write of nothing opens the new file
+			if _, err := w.Logger.Write(nil); err == nil {
+				w.updateLink()
+			}
+		}
+	}
+	w.scheduleRotation(now)
+	onTimerRotate := w.onTimerRotate
+	w.mu.Unlock()
+
+	if rotated && onTimerRotate != nil {
+		onTimerRotate()
+	}
+}
+
+// reopen closes the current file, which the next write opens again
+func (w *TimeRotatingWriter) reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.Logger.Close()
+}
+
+// Close stops the rotation timer and closes the current file
+func (w *TimeRotatingWriter) Close() error {
+	w.mu.Lock()
+	w.closed = true
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.mu.Unlock()
+	return w.Logger.Close()
+}
+
+// nextRotationTime returns the start of the interval after the one holding
+// now, or false if the interval is unknown
+func nextRotationTime(now time.Time, interval TimeRotationInterval, weekStart time.Weekday) (time.Time, bool) {
+	year, month, day := now.Date()
+	loc := now.Location()
+	switch interval {
+	case RotationHourly:
+		return time.Date(year, month, day, now.Hour()+1, 0, 0, 0, loc), true
+	case RotationDaily:
+		return time.Date(year, month, day+1, 0, 0, 0, 0, loc), true
+	case RotationWeekly:
+		return periodStart(now, interval, weekStart).AddDate(0, 0, 7), true
+	case RotationMonthly:
+		return time.Date(year, month+1, 1, 0, 0, 0, 0, loc), true
 	}
+	return time.Time{}, false
+}
+
+// syncFile flushes a file to disk. lumberjack does not expose its file, so
+// the file is opened again; a sync through any descriptor flushes it.
+func syncFile(path string) {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return
+	}
+	f.Sync()
+	f.Close()
 }
 
 // Write implements io.Writer interface with time-based rotation check
@@ -75,12 +275,7 @@ func (w *TimeRotatingWriter) Write(p []byte) (n int, err error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	now := time.Now()
-	if w.options.LocalTime {
-		now = now.Local()
-	} else {
-		now = now.UTC()
-	}
+	now := w.now()
 
 	// Check if we need to rotate based on time
 	if w.shouldRotateByTime(now) {
@@ -89,7 +284,80 @@ func (w *TimeRotatingWriter) Write(p []byte) (n int, err error) {
 		}
 	}
 
-	return w.Logger.Write(p)
+	n, err = w.Logger.Write(p)
+	if err == nil {
+		w.updateLink()
+	}
+	return n, err
+}
+
+// updateLink points the symlink to the current file; w.mu must be held
+func (w *TimeRotatingWriter) updateLink() {
+	if w.options.Symlink && w.linked != w.Logger.Filename && w.linkFailed != w.Logger.Filename {
+		if err := linkActiveFile(linkFilename(w.baseFilename), w.Logger.Filename); err != nil {
+			// Linking is retried at the next rotation only
+			w.linkFailed = w.Logger.Filename
+			report := w.report
+			if report == nil {
+				report = Config{}.internalErrorHandler()
+			}
+			report(fmt.Errorf("logger: link %s: %w", w.Logger.Filename, err))
+			return
+		}
+		w.linked = w.Logger.Filename
+	}
+}
+
+// linkActiveFile points link to the active file. An existing link is
+// replaced only if it is a symlink or a hard link to a log file of link, so
+// that a regular log file is never overwritten.
+func linkActiveFile(link, active string) error {
+	if info, err := os.Lstat(link); err == nil && info.Mode()&os.ModeSymlink == 0 && !isLogHardLink(link, info) {
+		return fmt.Errorf("%s is not a link", link)
+	}
+
+	tmp := link + ".tmp"
+	os.Remove(tmp)
+	var err error
+	if runtime.GOOS == "windows" {
+		err = os.Link(active, tmp)
+	} else {
+		err = os.Symlink(filepath.Base(active), tmp)
+	}
+	if err != nil {
+		return err
+	}
+	if runtime.GOOS == "windows" {
+		// Windows does not rename over an existing file
+		os.Remove(link)
+	}
+	return os.Rename(tmp, link)
+}
+
+// isLogHardLink reports whether the file at link is a hard link to one of
+// the time-rotated files of link
+func isLogHardLink(link string, info os.FileInfo) bool {
+	for _, backup := range rotatedBackups(link, "") {
+		if other, err := os.Stat(backup); err == nil && os.SameFile(info, other) {
+			return true
+		}
+	}
+	return false
+}
+
+// Rotate closes the current file and starts a new one, keeping the current
+// time period
+func (w *TimeRotatingWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.Logger.Rotate()
+}
+
+// currentFilename returns the file currently written to
+func (w *TimeRotatingWriter) currentFilename() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.Logger.Filename
 }
 
 // shouldRotateByTime checks if rotation is needed based on time interval
@@ -105,9 +373,7 @@ func (w *TimeRotatingWriter) shouldRotateByTime(now time.Time) bool {
 			now.Month() != w.lastRotationTime.Month() ||
 			now.Year() != w.lastRotationTime.Year()
 	case RotationWeekly:
-		_, thisWeek := now.ISOWeek()
-		_, lastWeek := w.lastRotationTime.ISOWeek()
-		return thisWeek != lastWeek || now.Year() != w.lastRotationTime.Year()
+		return !periodStart(now, RotationWeekly, w.weekStart).Equal(periodStart(w.lastRotationTime, RotationWeekly, w.weekStart))
 	case RotationMonthly:
 		return now.Month() != w.lastRotationTime.Month() ||
 			now.Year() != w.lastRotationTime.Year()
@@ -123,17 +389,22 @@ func (w *TimeRotatingWriter) rotateByTime(now time.Time) error {
 	}
 
 	// Generate new filename with current timestamp
-	newFilename := generateTimestampedFilename(w.baseFilename, now, w.currentTimeFormat)
+	newFilename := generateTimestampedFilename(w.baseFilename, periodStart(now, w.options.TimeRotationInterval, w.weekStart), w.currentTimeFormat)
 
 	// Update lumberjack logger with new filename
 	w.Logger.Filename = newFilename
 	w.lastRotationTime = now
+	w.cleanup()
 
 	return nil
 }
 
-// generateTimestampedFilename creates a filename with timestamp
+// generateTimestampedFilename creates a filename with timestamp, placed at
+// the {time} placeholder if any
 func generateTimestampedFilename(baseFilename string, t time.Time, timeFormat string) string {
+	if strings.Contains(baseFilename, timePlaceholder) {
+		return strings.ReplaceAll(baseFilename, timePlaceholder, t.Format(timeFormat))
+	}
 	dir := filepath.Dir(baseFilename)
 	filename := filepath.Base(baseFilename)
 	ext := filepath.Ext(filename)
@@ -144,3 +415,215 @@ func generateTimestampedFilename(baseFilename string, t time.Time, timeFormat st
 
 	return filepath.Join(dir, timestampedName)
 }
+
+// rotateFunc is called after a rotation with the path of the rotated file
+type rotateFunc func(oldPath string, at time.Time)
+
+// rotationWatcher calls its callbacks after each rotation of a file writer.
+// lumberjack does not expose its rotations, so the watcher tracks the file
+// size with the same rule lumberjack rotates by, and detects time-based
+// rotations from the change of the current filename.
+type rotationWatcher struct {
+	zapcore.WriteSyncer
+	base     string
+	refresh  bool
+	maxSize  int64
+	filename func() string
+	onRotate []rotateFunc
+
+	mu      sync.Mutex
+	current string
+	size    int64
+}
+
+func newRotationWatcher(w zapcore.WriteSyncer, file io.Writer, options FileOptions, onRotate ...rotateFunc) *rotationWatcher {
+	maxSize := int64(options.MaxSize) * 1024 * 1024
+	if maxSize <= 0 {
+		maxSize = 100 * 1024 * 1024 // lumberjack's default
+	}
+	if options.RotationMode == RotationModeNone {
+		maxSize = 0
+	}
+	watcher := &rotationWatcher{
+		WriteSyncer: w,
+		base:        options.Filename,
+		refresh:     options.Lock,
+		maxSize:     maxSize,
+		filename:    activeFilename(file, options),
+		onRotate:    onRotate,
+	}
+	if trw, ok := file.(*TimeRotatingWriter); ok {
+		trw.mu.Lock()
+		trw.onTimerRotate = watcher.checkFilename
+		trw.mu.Unlock()
+	}
+	return watcher
+}
+
+func (w *rotationWatcher) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	first := w.current == ""
+	if first || w.refresh {
+		// Other processes also write to a locked file
+		if first {
+			w.current = w.filename()
+		}
+		if info, err := os.Stat(w.current); err == nil {
+			w.size = info.Size()
+		}
+	}
+	// Like lumberjack, rotate an existing file opened by the first write
+	// when the write would reach the limit, an open file when it would
+	// exceed it
+	size := w.size + int64(len(p))
+	bySize := w.maxSize > 0 && w.size > 0 && (size > w.maxSize || first && size == w.maxSize)
+	if bySize {
+		w.size = 0
+	}
+
+	n, err := w.WriteSyncer.Write(p)
+	oldPath := ""
+	if name := w.filename(); name != w.current {
+		oldPath = w.current
+		w.current = name
+		w.size = 0
+	} else if bySize {
+		// lumberjack renamed the file to the newest backup
+		if backups := rotatedBackups(w.base, w.current); len(backups) > 0 {
+			oldPath = backups[len(backups)-1]
+		}
+	}
+	w.size += int64(n)
+
+	if bySize || oldPath != "" {
+		w.rotated(oldPath)
+	}
+	return n, err
+}
+
+// checkFilename calls the callbacks if the current filename changed since
+// the last write
+func (w *rotationWatcher) checkFilename() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.current == "" {
+		return
+	}
+	if name := w.filename(); name != w.current {
+		oldPath := w.current
+		w.current = name
+		w.size = 0
+		w.rotated(oldPath)
+	}
+}
+
+// rotateWith returns a func forcing a rotation with rotate and calling the
+// callbacks
+func (w *rotationWatcher) rotateWith(rotate func() error) func() error {
+	return func() error {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		if err := rotate(); err != nil {
+			return err
+		}
+		w.current = w.filename()
+		w.size = 0
+		oldPath := ""
+		if backups := rotatedBackups(w.base, w.current); len(backups) > 0 {
+			oldPath = backups[len(backups)-1]
+		}
+		w.rotated(oldPath)
+		return nil
+	}
+}
+
+// rotated calls the callbacks; w.mu must be held
+func (w *rotationWatcher) rotated(oldPath string) {
+	now := time.Now()
+	for _, fn := range w.onRotate {
+		fn(oldPath, now)
+	}
+}
+
+// activeFilename returns a func reporting the file a file writer currently
+// writes to
+func activeFilename(file io.Writer, options FileOptions) func() string {
+	switch f := file.(type) {
+	case *TimeRotatingWriter:
+		return f.currentFilename
+	case *lumberjack.Logger:
+		return func() string { return f.Filename }
+	case *reopenableFile:
+		return func() string { return f.path }
+	}
+	return func() string { return options.Filename }
+}
+
+// backupRetention removes the oldest rotated backups of a log file while
+// the file and its backups exceed FileOptions.MaxTotalSize. For backups
+// lumberjack does not recognize, such as zstd compressed or encrypted ones,
+// it also applies MaxBackups and MaxAge.
+type backupRetention struct {
+	filename   string
+	active     func() string
+	maxBytes   int64
+	maxBackups int
+	maxAge     time.Duration
+}
+
+// newBackupRetention returns the retention of a log file, or nil if
+// lumberjack applies all of its limits
+func newBackupRetention(options FileOptions, file io.Writer) *backupRetention {
+	r := &backupRetention{
+		filename: options.Filename,
+		active:   activeFilename(file, options),
+		maxBytes: int64(options.MaxTotalSize) * 1024 * 1024,
+	}
+	if options.compression() == CompressionZstd || len(options.EncryptRecipients) > 0 {
+		r.maxBackups = options.MaxBackups
+		r.maxAge = time.Duration(options.MaxAge) * 24 * time.Hour
+	}
+	if r.maxBytes <= 0 && r.maxBackups <= 0 && r.maxAge <= 0 {
+		return nil
+	}
+	return r
+}
+
+// enforce removes backups, oldest first, until they fit the limits. The
+// active file is never removed.
+func (r *backupRetention) enforce() {
+	active := r.active()
+	backups := rotatedBackups(r.filename, active)
+	infos := make([]os.FileInfo, len(backups))
+
+	var total int64
+	if info, err := os.Stat(active); err == nil {
+		total = info.Size()
+	}
+	for i, backup := range backups {
+		if info, err := os.Stat(backup); err == nil {
+			infos[i] = info
+			total += info.Size()
+		}
+	}
+
+	cutoff := time.Now().Add(-r.maxAge)
+	for i, backup := range backups {
+		if infos[i] == nil {
+			continue
+		}
+		expired := r.maxAge > 0 && infos[i].ModTime().Before(cutoff)
+		surplus := r.maxBackups > 0 && len(backups)-i > r.maxBackups
+		oversize := r.maxBytes > 0 && total > r.maxBytes
+		if !expired && !surplus && !oversize {
+			continue
+		}
+		if err := os.Remove(backup); err == nil {
+			total -= infos[i].Size()
+		}
+	}
+}
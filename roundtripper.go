@@ -0,0 +1,281 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// defaultRedactedHeaders are masked whenever headers are logged
+var defaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// RoundTripperOption configures NewRoundTripper
+type RoundTripperOption func(*roundTripper)
+
+// WithTransportLogger logs the outbound requests through l instead of the
+// global logger
+func WithTransportLogger(l Logger) RoundTripperOption {
+	return func(rt *roundTripper) { rt.logger = l }
+}
+
+// WithHeaderLogging adds the request and response headers to the entries.
+// Authorization, Proxy-Authorization, Cookie and Set-Cookie are masked.
+func WithHeaderLogging() RoundTripperOption {
+	return func(rt *roundTripper) { rt.headers = true }
+}
+
+// WithRedactedHeaders masks more headers when headers are logged
+func WithRedactedHeaders(names ...string) RoundTripperOption {
+	return func(rt *roundTripper) {
+		for _, name := range names {
+			rt.redactedHeaders[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+}
+
+// WithRedactedQueryParams masks the values of URL query parameters, e.g.
+// "api_key"
+func WithRedactedQueryParams(names ...string) RoundTripperOption {
+	return func(rt *roundTripper) {
+		for _, name := range names {
+			rt.redactedParams[name] = true
+		}
+	}
+}
+
+// WithBodyLogging adds the first maxBytes of the request and response
+// bodies to the entries
+func WithBodyLogging(maxBytes int) RoundTripperOption {
+	return func(rt *roundTripper) { rt.maxBody = maxBytes }
+}
+
+// WithBodyRedactor rewrites logged bodies, e.g. to mask credentials. It
+// only changes the log entries, not the bodies sent or received.
+func WithBodyRedactor(redact func(body []byte) []byte) RoundTripperOption {
+	return func(rt *roundTripper) { rt.redactBody = redact }
+}
+
+// WithRetries retries idempotent requests up to max times, waiting backoff
+// times the attempt number in between, when the transport fails or the
+// server answers 502, 503 or 504. Each retry is logged at warn level.
+func WithRetries(max int, backoff time.Duration) RoundTripperOption {
+	return func(rt *roundTripper) {
+		rt.retries = max
+		rt.backoff = backoff
+	}
+}
+
+// roundTripper logs the requests sent through its base transport
+type roundTripper struct {
+	base            http.RoundTripper
+	logger          Logger
+	headers         bool
+	redactedHeaders map[string]bool
+	redactedParams  map[string]bool
+	maxBody         int
+	redactBody      func([]byte) []byte
+	retries         int
+	backoff         time.Duration
+}
+
+// NewRoundTripper returns a transport logging each request sent through
+// base with its method, URL, status, latency and number of retries, for use
+// as the Transport of any http.Client. A nil base uses
+// http.DefaultTransport. Successful requests are logged at info level,
+// 4xx responses at warn and 5xx responses and transport errors at error.
+func NewRoundTripper(base http.RoundTripper, opts ...RoundTripperOption) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	rt := &roundTripper{
+		base:            base,
+		redactedHeaders: make(map[string]bool),
+		redactedParams:  make(map[string]bool),
+	}
+	for _, name := range defaultRedactedHeaders {
+		rt.redactedHeaders[name] = true
+	}
+	for _, opt := range opts {
+		opt(rt)
+	}
+	return rt
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	l := rt.logger
+	if l == nil {
+		l = GetLogger()
+	}
+	start := time.Now()
+
+	fields := []zap.Field{
+		zap.String("method", req.Method),
+		zap.String("url", rt.redactURL(req.URL)),
+	}
+	if rt.headers {
+		fields = append(fields, rt.headerField("request_headers", req.Header))
+	}
+	if rt.maxBody > 0 && req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			fields = append(fields, rt.bodyField("request_body", body))
+		}
+	}
+
+	var resp *http.Response
+	var err error
+	retries := 0
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 {
+			if attemptReq, err = rewindBody(req); err != nil {
+				break
+			}
+		}
+		resp, err = rt.base.RoundTrip(attemptReq)
+		if attempt >= rt.retries || !retryable(req, resp, err) {
+			break
+		}
+		retryFields := append(fields[:len(fields):len(fields)], zap.Int("attempt", attempt+1))
+		if err != nil {
+			retryFields = append(retryFields, Err(err))
+		} else {
+			retryFields = append(retryFields, zap.Int("status", resp.StatusCode))
+			// Release the connection of the failed attempt
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		l.Warn("http request retry", retryFields...)
+		retries++
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(rt.backoff * time.Duration(attempt+1)):
+		}
+	}
+
+	fields = append(fields, Latency(start))
+	if retries > 0 {
+		fields = append(fields, zap.Int("retries", retries))
+	}
+	if err != nil {
+		l.Error("http request failed", append(fields, Err(err))...)
+		return nil, err
+	}
+	fields = append(fields, zap.Int("status", resp.StatusCode))
+	if rt.headers {
+		fields = append(fields, rt.headerField("response_headers", resp.Header))
+	}
+	if rt.maxBody > 0 && resp.Body != nil {
+		fields = append(fields, rt.peekResponseBody(resp))
+	}
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError:
+		l.Error("http request", fields...)
+	case resp.StatusCode >= http.StatusBadRequest:
+		l.Warn("http request", fields...)
+	default:
+		l.Info("http request", fields...)
+	}
+	return resp, nil
+}
+
+// retryable reports whether an attempt can be retried: an idempotent
+// request whose body can be replayed that failed in transport or got a 502,
+// 503 or 504
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// rewindBody returns a copy of the request with a fresh body for another
+// attempt, since a transport must not modify the request
+func rewindBody(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.GetBody == nil {
+		return clone, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	clone.Body = body
+	return clone, nil
+}
+
+// redactURL returns the URL without its password and with the values of
+// the redacted query parameters masked
+func (rt *roundTripper) redactURL(u *url.URL) string {
+	if len(rt.redactedParams) == 0 || u.RawQuery == "" {
+		return u.Redacted()
+	}
+	params := strings.Split(u.RawQuery, "&")
+	for i, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		if name, err := url.QueryUnescape(key); err == nil && rt.redactedParams[name] {
+			params[i] = key + "=" + DefaultRedactMask
+		}
+	}
+	redacted := *u
+	redacted.RawQuery = strings.Join(params, "&")
+	return redacted.Redacted()
+}
+
+// headerField returns the headers with the redacted ones masked
+func (rt *roundTripper) headerField(key string, header http.Header) zap.Field {
+	values := make(map[string]any, len(header))
+	for name, v := range header {
+		if rt.redactedHeaders[http.CanonicalHeaderKey(name)] {
+			values[name] = DefaultRedactMask
+			continue
+		}
+		values[name] = strings.Join(v, ", ")
+	}
+	return Dict(key, Fields(values)...)
+}
+
+// bodyField returns the first maxBytes of body, which it closes
+func (rt *roundTripper) bodyField(key string, body io.ReadCloser) zap.Field {
+	defer body.Close()
+	data, _ := io.ReadAll(io.LimitReader(body, int64(rt.maxBody)))
+	return zap.String(key, string(rt.redact(data)))
+}
+
+// peekResponseBody returns the field with the start of the response body,
+// leaving the body readable by the caller
+func (rt *roundTripper) peekResponseBody(resp *http.Response) zap.Field {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, int64(rt.maxBody)))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+	return zap.String("response_body", string(rt.redact(data)))
+}
+
+func (rt *roundTripper) redact(body []byte) []byte {
+	if rt.redactBody == nil {
+		return body
+	}
+	return rt.redactBody(bytes.Clone(body))
+}
@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// routeRule is a compiled RouteRule
+type routeRule struct {
+	field  string
+	equals string
+	prefix string
+	regex  *regexp.Regexp
+}
+
+// compileRoute compiles a route rule
+func compileRoute(rule RouteRule) (routeRule, error) {
+	if rule.Field == "" || rule.Sink == "" {
+		return routeRule{}, fmt.Errorf("logger: route rule needs a field and a sink")
+	}
+	compiled := routeRule{field: rule.Field, equals: rule.Equals, prefix: rule.Prefix}
+	if rule.Regex != "" {
+		re, err := regexp.Compile(rule.Regex)
+		if err != nil {
+			return routeRule{}, fmt.Errorf("logger: invalid route regex %q: %w", rule.Regex, err)
+		}
+		compiled.regex = re
+	}
+	return compiled, nil
+}
+
+// match reports whether fields contain the rule field with a matching value
+func (r routeRule) match(fields []zapcore.Field) bool {
+	value, ok := fieldValue(fields, r.field)
+	switch {
+	case !ok:
+		return false
+	case r.equals != "" && value != r.equals:
+		return false
+	case r.prefix != "" && !strings.HasPrefix(value, r.prefix):
+		return false
+	case r.regex != nil && !r.regex.MatchString(value):
+		return false
+	}
+	return true
+}
+
+// routeCore writes to a sink only the entries matching one of its route
+// rules, in their own fields or in those added through With. The rules are
+// read from the rule set on every entry; a sink without rules receives
+// every entry.
+type routeCore struct {
+	zapcore.Core
+	rules *RuleSet
+	sink  string
+
+	// fields are the fields added through With, matched along with the
+	// entry fields
+	fields []zapcore.Field
+}
+
+func newRouteCore(core zapcore.Core, rules *RuleSet, sink string) zapcore.Core {
+	return &routeCore{Core: core, rules: rules, sink: sink}
+}
+
+func (c *routeCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	clone.fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	return &clone
+}
+
+func (c *routeCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	rules := c.rules.load().routes[c.sink]
+	if len(rules) == 0 {
+		return c.Core.Check(ent, ce)
+	}
+	inner := c.Core.Check(ent, nil)
+	if inner == nil {
+		return ce
+	}
+	return ce.AddCore(inner.Entry, &routeWriter{core: c, checked: inner, rules: rules})
+}
+
+// routeWriter is a single-use core that writes an entry through the
+// CheckedEntry of the wrapped core if its fields match a rule
+type routeWriter struct {
+	core    *routeCore
+	checked *zapcore.CheckedEntry
+	rules   []routeRule
+}
+
+func (w *routeWriter) Enabled(zapcore.Level) bool { return true }
+
+func (w *routeWriter) With([]zapcore.Field) zapcore.Core { return w }
+
+func (w *routeWriter) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, w)
+}
+
+func (w *routeWriter) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	all := fields
+	if len(w.core.fields) > 0 {
+		all = append(w.core.fields[:len(w.core.fields):len(w.core.fields)], fields...)
+	}
+	for _, rule := range w.rules {
+		if rule.match(all) {
+			return writeChecked(w.checked, ent, fields)
+		}
+	}
+	return nil
+}
+
+func (w *routeWriter) Sync() error { return nil }
@@ -0,0 +1,197 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Rule is a drop filter, level override or routing rule that can be added
+// and removed at runtime through a RuleSet. Exactly one of Drop,
+// LevelOverride and Route must be set.
+type Rule struct {
+	// ID identifies the rule for Remove. Add assigns one if empty.
+	ID string `json:"id" yaml:"id"`
+
+	Drop          *DropFilter    `json:"drop,omitempty" yaml:"drop,omitempty"`
+	LevelOverride *LevelOverride `json:"level_override,omitempty" yaml:"level_override,omitempty"`
+	Route         *RouteRule     `json:"route,omitempty" yaml:"route,omitempty"`
+}
+
+// compiledRule is a rule with its compiled form
+type compiledRule struct {
+	rule     Rule
+	drop     entryMatcher
+	override levelOverride
+	route    routeRule
+}
+
+// ruleSnapshot holds the compiled rules read by the cores
+type ruleSnapshot struct {
+	drops     []entryMatcher
+	overrides []levelOverride
+	routes    map[string][]routeRule // by target sink
+}
+
+// RuleSet holds the drop filters, level overrides and routing rules of a
+// logger tree, so that they can be changed at runtime, e.g. to silence a
+// flood of entries or send a component to its own file during an
+// incident. It starts with the rules of the configuration; reads use an
+// immutable snapshot so the logging path never locks.
+type RuleSet struct {
+	mu       sync.Mutex // serializes writers
+	rules    []compiledRule
+	next     int
+	snapshot atomic.Pointer[ruleSnapshot]
+}
+
+// newRuleSet creates the rule set from the configured rules
+func newRuleSet(config Config) (*RuleSet, error) {
+	r := &RuleSet{}
+	var rules []Rule
+	for i := range config.Drop {
+		rules = append(rules, Rule{Drop: &config.Drop[i]})
+	}
+	for i := range config.LevelOverrides {
+		rules = append(rules, Rule{LevelOverride: &config.LevelOverrides[i]})
+	}
+	for i := range config.Routes {
+		rules = append(rules, Rule{Route: &config.Routes[i]})
+	}
+	for _, rule := range rules {
+		if _, err := r.add(rule); err != nil {
+			return nil, err
+		}
+	}
+	r.publish()
+	return r, nil
+}
+
+// Add adds a rule and returns its ID
+func (r *RuleSet) Add(rule Rule) (string, error) {
+	if r == nil {
+		return "", errNotZapLogger
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id, err := r.add(rule)
+	if err != nil {
+		return "", err
+	}
+	r.publish()
+	return id, nil
+}
+
+// add compiles and appends a rule; callers must hold mu or own r
+// exclusively
+func (r *RuleSet) add(rule Rule) (string, error) {
+	compiled, err := compileRule(rule)
+	if err != nil {
+		return "", err
+	}
+	if compiled.rule.ID == "" {
+		r.next++
+		compiled.rule.ID = "rule-" + strconv.Itoa(r.next)
+	}
+	for _, existing := range r.rules {
+		if existing.rule.ID == compiled.rule.ID {
+			return "", fmt.Errorf("logger: duplicate rule ID %q", compiled.rule.ID)
+		}
+	}
+	r.rules = append(r.rules, compiled)
+	return compiled.rule.ID, nil
+}
+
+// Remove removes the rule with the given ID and reports whether it existed
+func (r *RuleSet) Remove(id string) bool {
+	if r == nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, compiled := range r.rules {
+		if compiled.rule.ID == id {
+			r.rules = append(r.rules[:i:i], r.rules[i+1:]...)
+			r.publish()
+			return true
+		}
+	}
+	return false
+}
+
+// List returns the rules in the order they apply
+func (r *RuleSet) List() []Rule {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rules := make([]Rule, len(r.rules))
+	for i, compiled := range r.rules {
+		rules[i] = compiled.rule
+	}
+	return rules
+}
+
+// publish stores a new snapshot; callers must hold mu or own r exclusively
+func (r *RuleSet) publish() {
+	s := &ruleSnapshot{routes: make(map[string][]routeRule)}
+	for _, compiled := range r.rules {
+		switch {
+		case compiled.rule.Drop != nil:
+			s.drops = append(s.drops, compiled.drop)
+		case compiled.rule.LevelOverride != nil:
+			s.overrides = append(s.overrides, compiled.override)
+		case compiled.rule.Route != nil:
+			sink := compiled.rule.Route.Sink
+			s.routes[sink] = append(s.routes[sink], compiled.route)
+		}
+	}
+	r.snapshot.Store(s)
+}
+
+// load returns the current snapshot
+func (r *RuleSet) load() *ruleSnapshot {
+	return r.snapshot.Load()
+}
+
+// compileRule validates and compiles a rule. The rule keeps copies of its
+// definition so that callers cannot change it after adding it.
+func compileRule(rule Rule) (compiledRule, error) {
+	set := 0
+	for _, isSet := range []bool{rule.Drop != nil, rule.LevelOverride != nil, rule.Route != nil} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return compiledRule{}, fmt.Errorf("logger: rule must have exactly one of drop, level override and route")
+	}
+
+	compiled := compiledRule{rule: Rule{ID: rule.ID}}
+	var err error
+	switch {
+	case rule.Drop != nil:
+		drop := *rule.Drop
+		compiled.rule.Drop = &drop
+		compiled.drop, err = compileDropFilter(drop)
+	case rule.LevelOverride != nil:
+		override := *rule.LevelOverride
+		compiled.rule.LevelOverride = &override
+		compiled.override, err = compileLevelOverride(override)
+	default:
+		route := *rule.Route
+		compiled.rule.Route = &route
+		compiled.route, err = compileRoute(route)
+	}
+	return compiled, err
+}
+
+// Rules returns the runtime rules of the logger tree
+func (l *ZapLogger) Rules() *RuleSet {
+	return l.rules
+}
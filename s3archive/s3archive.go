@@ -0,0 +1,100 @@
+// Package s3archive uploads rotated log files to Amazon S3.
+//
+//	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	uploader := s3archive.New(s3.NewFromConfig(cfg), "my-log-bucket")
+//	config := logger.ProductionConfigWithFile("logs/app.log").
+//		WithArchive(uploader, "app/{host}/{date}", true)
+package s3archive
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// Client is the part of the S3 API used by the Uploader, implemented by
+// *s3.Client
+type Client interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error)
+}
+
+// Uploader implements logger.Uploader for an S3 bucket
+type Uploader struct {
+	client Client
+	bucket string
+}
+
+// New creates an Uploader storing files in bucket
+func New(client Client, bucket string) *Uploader {
+	return &Uploader{client: client, bucket: bucket}
+}
+
+// Upload puts the file under key with its SHA-256 checksum, which S3
+// verifies on receipt, then verifies the stored object reports the same
+// checksum and size
+func (u *Uploader) Upload(ctx context.Context, key, localPath string) error {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	checksum := base64.StdEncoding.EncodeToString(hash.Sum(nil))
+
+	contentType := "text/plain"
+	if strings.HasSuffix(localPath, ".gz") {
+		contentType = "application/gzip"
+	}
+	if _, err := u.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(u.bucket),
+		Key:           aws.String(key),
+		Body:          f,
+		ContentLength: aws.Int64(info.Size()),
+		ContentType:   aws.String(contentType),
+
+		ChecksumAlgorithm: types.ChecksumAlgorithmSha256,
+		ChecksumSHA256:    aws.String(checksum),
+	}); err != nil {
+		return fmt.Errorf("s3archive: put s3://%s/%s: %w", u.bucket, key, err)
+	}
+
+	head, err := u.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:       aws.String(u.bucket),
+		Key:          aws.String(key),
+		ChecksumMode: types.ChecksumModeEnabled,
+	})
+	if err != nil {
+		return fmt.Errorf("s3archive: verify s3://%s/%s: %w", u.bucket, key, err)
+	}
+	if size := aws.ToInt64(head.ContentLength); size != info.Size() {
+		return fmt.Errorf("s3archive: verify s3://%s/%s: stored %d bytes, want %d", u.bucket, key, size, info.Size())
+	}
+	if stored := aws.ToString(head.ChecksumSHA256); stored != checksum {
+		return fmt.Errorf("s3archive: verify s3://%s/%s: stored SHA-256 %q, want %q", u.bucket, key, stored, checksum)
+	}
+	return nil
+}
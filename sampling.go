@@ -0,0 +1,175 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// newSamplingCore caps the volume of entries sharing a level and message.
+// Within each tick the first Initial entries are logged, then every
+// Thereafter-th entry; the rest are dropped. Levels can have their own rate,
+// and levels at or above UnsampledLevel are never sampled.
+func newSamplingCore(core zapcore.Core, options SamplingOptions, metrics MetricsRecorder) zapcore.Core {
+	tick := options.Tick
+	if tick <= 0 {
+		tick = time.Second
+	}
+	hook := zapcore.SamplerHook(func(_ zapcore.Entry, dec zapcore.SamplingDecision) {
+		if dec&zapcore.LogDropped != 0 {
+			metrics.RecordDropped(DropReasonSampled, 1)
+		}
+	})
+	defaultRate := SamplingRate{Initial: options.Initial, Thereafter: options.Thereafter}
+	if len(options.Levels) == 0 && options.UnsampledLevel == "" {
+		return zapcore.NewSamplerWithOptions(core, tick, defaultRate.Initial, defaultRate.Thereafter, hook)
+	}
+
+	unsampled := zapcore.InvalidLevel
+	if options.UnsampledLevel != "" {
+		if level, err := zapcore.ParseLevel(options.UnsampledLevel); err == nil {
+			unsampled = level
+		}
+	}
+
+	rates := make(map[zapcore.Level]SamplingRate, len(options.Levels))
+	for name, rate := range options.Levels {
+		if level, err := zapcore.ParseLevel(name); err == nil {
+			rates[level] = rate
+		}
+	}
+
+	// Levels sharing a rate share a sampler, and so its counters
+	samplers := map[SamplingRate]zapcore.Core{}
+	byLevel := map[zapcore.Level]zapcore.Core{}
+	for level := zapcore.DebugLevel; level <= zapcore.FatalLevel; level++ {
+		if unsampled != zapcore.InvalidLevel && level >= unsampled {
+			continue
+		}
+		rate, ok := rates[level]
+		if !ok {
+			rate = defaultRate
+		}
+		sampler, ok := samplers[rate]
+		if !ok {
+			sampler = zapcore.NewSamplerWithOptions(core, tick, rate.Initial, rate.Thereafter, hook)
+			samplers[rate] = sampler
+		}
+		byLevel[level] = sampler
+	}
+
+	return &levelSamplingCore{Core: core, byLevel: byLevel}
+}
+
+// levelSamplingCore dispatches entries to the sampler configured for their
+// level; levels without a sampler go straight to the wrapped core
+type levelSamplingCore struct {
+	zapcore.Core
+	byLevel map[zapcore.Level]zapcore.Core
+}
+
+func (c *levelSamplingCore) With(fields []zapcore.Field) zapcore.Core {
+	clones := make(map[zapcore.Core]zapcore.Core, len(c.byLevel))
+	byLevel := make(map[zapcore.Level]zapcore.Core, len(c.byLevel))
+	for level, sampler := range c.byLevel {
+		clone, ok := clones[sampler]
+		if !ok {
+			clone = sampler.With(fields)
+			clones[sampler] = clone
+		}
+		byLevel[level] = clone
+	}
+	return &levelSamplingCore{Core: c.Core.With(fields), byLevel: byLevel}
+}
+
+func (c *levelSamplingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if sampler, ok := c.byLevel[ent.Level]; ok {
+		return sampler.Check(ent, ce)
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// samplingState holds the sampler of a logger tree, so that the sampling
+// options can change at runtime with SetSampling
+type samplingState struct {
+	mu      sync.Mutex // serializes writers
+	root    zapcore.Core
+	metrics MetricsRecorder
+	current atomic.Pointer[sampler]
+}
+
+// sampler is the sampling core built for one version of the options; a nil
+// core disables sampling
+type sampler struct {
+	generation uint64
+	core       zapcore.Core
+}
+
+// newDynamicSamplingCore wraps core with the sampling stage, returning the
+// state that changes its options
+func newDynamicSamplingCore(core zapcore.Core, options SamplingOptions, metrics MetricsRecorder) (zapcore.Core, *samplingState) {
+	state := &samplingState{root: core, metrics: metrics}
+	state.set(options)
+	return &dynamicSamplingCore{Core: core, state: state}, state
+}
+
+// set builds the sampler for options
+func (s *samplingState) set(options SamplingOptions) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := &sampler{}
+	if current := s.current.Load(); current != nil {
+		next.generation = current.generation + 1
+	}
+	if options.Enabled {
+		next.core = newSamplingCore(s.root, options, s.metrics)
+	}
+	s.current.Store(next)
+}
+
+// dynamicSamplingCore samples entries with the current sampler of its
+// state. Loggers derived through With build their sampler from the current
+// one on first use, so that they share its counters.
+type dynamicSamplingCore struct {
+	zapcore.Core
+	state *samplingState
+
+	// fields are the fields added through With
+	fields []zapcore.Field
+
+	// cache is the current sampler with fields added
+	cache atomic.Pointer[sampler]
+}
+
+func (c *dynamicSamplingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &dynamicSamplingCore{
+		Core:   c.Core.With(fields),
+		state:  c.state,
+		fields: append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *dynamicSamplingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	current := c.state.current.Load()
+	if current.core == nil {
+		return c.Core.Check(ent, ce)
+	}
+	if len(c.fields) == 0 {
+		return current.core.Check(ent, ce)
+	}
+	cached := c.cache.Load()
+	if cached == nil || cached.generation != current.generation {
+		cached = &sampler{generation: current.generation, core: current.core.With(c.fields)}
+		c.cache.Store(cached)
+	}
+	return cached.core.Check(ent, ce)
+}
+
+// SetSampling changes the sampling options at runtime, e.g. to sample less
+// while investigating an incident. Disabled options turn sampling off.
+func (l *ZapLogger) SetSampling(options SamplingOptions) {
+	l.sampling.set(options)
+}
@@ -0,0 +1,270 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Scrubbing preset names
+const (
+	ScrubCreditCard  = "credit_card"
+	ScrubEmail       = "email"
+	ScrubBearerToken = "bearer_token"
+	ScrubJWT         = "jwt"
+)
+
+// scrubPresets are the built-in PII patterns
+var scrubPresets = map[string]string{
+	ScrubCreditCard:  `\b(?:\d[ -]?){12,18}\d\b`,
+	ScrubEmail:       `[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`,
+	ScrubBearerToken: `(?i)bearer\s+[A-Za-z0-9\-._~+/]+=*`,
+	ScrubJWT:         `\beyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+`,
+}
+
+// scrubber replaces PII patterns in messages and string values
+type scrubber struct {
+	patterns    []*regexp.Regexp
+	replacement string
+}
+
+func newScrubber(options ScrubbingOptions) (*scrubber, error) {
+	s := &scrubber{replacement: options.Replacement}
+	if s.replacement == "" {
+		s.replacement = DefaultRedactMask
+	}
+
+	for _, preset := range options.Presets {
+		pattern, ok := scrubPresets[preset]
+		if !ok {
+			return nil, fmt.Errorf("logger: unknown scrubbing preset %q", preset)
+		}
+		s.patterns = append(s.patterns, regexp.MustCompile(pattern))
+	}
+	for _, pattern := range options.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("logger: invalid scrubbing pattern %q: %w", pattern, err)
+		}
+		s.patterns = append(s.patterns, re)
+	}
+	return s, nil
+}
+
+// scrubString replaces every configured pattern in s
+func (s *scrubber) scrubString(v string) string {
+	for _, re := range s.patterns {
+		v = re.ReplaceAllString(v, s.replacement)
+	}
+	return v
+}
+
+// scrubFields returns fields with scrubbed string values and whether any changed
+func (s *scrubber) scrubFields(fields []zapcore.Field) ([]zapcore.Field, bool) {
+	var out []zapcore.Field
+	for i, f := range fields {
+		scrubbed, changed := s.scrubField(f)
+		if !changed {
+			if out != nil {
+				out = append(out, f)
+			}
+			continue
+		}
+		if out == nil {
+			out = make([]zapcore.Field, i, len(fields))
+			copy(out, fields[:i])
+		}
+		out = append(out, scrubbed)
+	}
+	if out == nil {
+		return fields, false
+	}
+	return out, true
+}
+
+func (s *scrubber) scrubField(f zapcore.Field) (zapcore.Field, bool) {
+	switch f.Type {
+	case zapcore.StringType:
+		if v := s.scrubString(f.String); v != f.String {
+			return zap.String(f.Key, v), true
+		}
+	case zapcore.ByteStringType:
+		if b, ok := f.Interface.([]byte); ok {
+			if v := s.scrubString(string(b)); v != string(b) {
+				return zap.ByteString(f.Key, []byte(v)), true
+			}
+		}
+	case zapcore.StringerType:
+		if v, ok := stringerValue(f.Interface); ok {
+			if scrubbed := s.scrubString(v); scrubbed != v {
+				return zap.String(f.Key, scrubbed), true
+			}
+		}
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok && err != nil {
+			if scrubbed, changed := s.scrubError(err); changed {
+				return zap.NamedError(f.Key, scrubbed), true
+			}
+		}
+	case zapcore.ObjectMarshalerType:
+		if dict, ok := f.Interface.(fieldObject); ok {
+			if scrubbed, changed := s.scrubFields(dict); changed {
+				return zap.Object(f.Key, fieldObject(scrubbed)), true
+			}
+			break
+		}
+		return s.scrubEncoded(f)
+	case zapcore.ArrayMarshalerType:
+		return s.scrubEncoded(f)
+	case zapcore.ReflectType:
+		if v, changed := s.scrubValue(f.Interface); changed {
+			return zap.Reflect(f.Key, v), true
+		}
+	}
+	return f, false
+}
+
+// scrubEncoded scrubs an object or array field through its encoded values.
+// A changed field is replaced by the scrubbed values, reflected.
+func (s *scrubber) scrubEncoded(f zapcore.Field) (zapcore.Field, bool) {
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+	v, ok := enc.Fields[f.Key]
+	if !ok {
+		return f, false
+	}
+	if v, changed := s.scrubValue(v); changed {
+		return zap.Reflect(f.Key, v), true
+	}
+	return f, false
+}
+
+// scrubValue scrubs the strings of a value; values other than strings,
+// numbers and the slices and maps of encoded fields are scrubbed through
+// their JSON form. The value is copied only when something changed.
+func (s *scrubber) scrubValue(v any) (any, bool) {
+	switch v := v.(type) {
+	case nil, bool, time.Time, time.Duration, json.Number:
+		return v, false
+	case string:
+		scrubbed := s.scrubString(v)
+		return scrubbed, scrubbed != v
+	case []any:
+		var out []any
+		for i, elem := range v {
+			scrubbed, changed := s.scrubValue(elem)
+			if changed && out == nil {
+				out = slices.Clone(v)
+			}
+			if out != nil {
+				out[i] = scrubbed
+			}
+		}
+		return out, out != nil
+	case map[string]any:
+		var out map[string]any
+		for key, elem := range v {
+			scrubbed, changed := s.scrubValue(elem)
+			if changed && out == nil {
+				out = maps.Clone(v)
+			}
+			if changed {
+				out[key] = scrubbed
+			}
+		}
+		return out, out != nil
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return v, false
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v, false
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var decoded any
+	if err := dec.Decode(&decoded); err != nil {
+		return v, false
+	}
+	if scrubbed, changed := s.scrubValue(decoded); changed {
+		return scrubbed, true
+	}
+	return v, false
+}
+
+// scrubError returns err with its message and %+v rendering scrubbed
+func (s *scrubber) scrubError(err error) (error, bool) {
+	msg := err.Error()
+	scrubbed := scrubbedError{msg: s.scrubString(msg)}
+	changed := scrubbed.msg != msg
+	if _, ok := err.(fmt.Formatter); ok {
+		verbose := fmt.Sprintf("%+v", err)
+		scrubbed.verbose = s.scrubString(verbose)
+		changed = changed || scrubbed.verbose != verbose
+	}
+	return scrubbed, changed
+}
+
+// scrubbedError replaces an error whose message or %+v rendering was scrubbed
+type scrubbedError struct {
+	msg     string
+	verbose string
+}
+
+func (e scrubbedError) Error() string { return e.msg }
+
+func (e scrubbedError) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('+') && e.verbose != "" {
+		io.WriteString(f, e.verbose)
+		return
+	}
+	io.WriteString(f, e.msg)
+}
+
+// stringerValue calls String on a Stringer field value, recovering from a
+// panic like zap does when encoding it
+func stringerValue(v any) (str string, ok bool) {
+	defer func() {
+		if recover() != nil {
+			str, ok = "", false
+		}
+	}()
+	stringer, ok := v.(fmt.Stringer)
+	if !ok {
+		return "", false
+	}
+	return stringer.String(), true
+}
+
+// newScrubbingCore scrubs messages and string fields added through With and per call
+func newScrubbingCore(core zapcore.Core, options ScrubbingOptions) (zapcore.Core, error) {
+	s, err := newScrubber(options)
+	if err != nil {
+		return nil, err
+	}
+	return newTransformCore(core, &entryTransform{
+		with: func(fields []zapcore.Field) []zapcore.Field {
+			fields, _ = s.scrubFields(fields)
+			return fields
+		},
+		write: func(ent *zapcore.Entry, fields []zapcore.Field) ([]zapcore.Field, bool) {
+			ent.Message = s.scrubString(ent.Message)
+			fields, _ = s.scrubFields(fields)
+			return fields, true
+		},
+	}), nil
+}
@@ -0,0 +1,398 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Sink names used as keys in Config.Sinks
+const (
+	SinkStdout = "stdout"
+	SinkStderr = "stderr"
+	SinkFile   = "file"
+	SinkWriter = "writer" // the writer given to NewLoggerWithWriter
+	SinkAudit  = "audit"  // the audit file
+)
+
+// sink is a named output destination
+type sink struct {
+	name   string
+	writer zapcore.WriteSyncer
+
+	// level is the minimum level written to the sink; empty means all
+	level string
+
+	// close releases background resources of the writer, if any
+	close func() error
+
+	// rotate forces a rotation of the file, if the sink is rotating
+	rotate func() error
+}
+
+// newSinks opens the outputs selected by the configuration
+func newSinks(config Config) ([]sink, error) {
+	sinks, err := newPrimarySinks(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Additional files
+	for _, options := range config.Files {
+		name := options.Name
+		if name == "" {
+			name = options.Filename
+		}
+		fileSink, err := newFileSink(name, options, config, false)
+		if err != nil {
+			closeSinks(sinks)
+			return nil, err
+		}
+		sinks = append(sinks, fileSink)
+	}
+	return sinks, nil
+}
+
+// closeSinks closes the sinks opened before a failure
+func closeSinks(sinks []sink) {
+	for _, s := range sinks {
+		if s.close != nil {
+			_ = s.close()
+		}
+	}
+}
+
+// newPrimarySinks opens the OutputPaths. "stdout" and "stderr" are the
+// standard streams, "file" (or FileOptions.Filename) is the rotating file of
+// FileOptions, and any other path or URL is opened with zap.Open. The
+// rotating file is written even when it is not listed.
+func newPrimarySinks(config Config) ([]sink, error) {
+	paths := config.OutputPaths
+	if len(paths) == 0 {
+		paths = []string{SinkStdout}
+	}
+
+	var sinks []sink
+	opened := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		name := path
+		if path == SinkFile || (path == config.FileOptions.Filename && path != "") {
+			name = SinkFile
+		}
+		if opened[name] {
+			continue
+		}
+
+		switch name {
+		case SinkStdout:
+			sinks = append(sinks, sink{name: SinkStdout, writer: zapcore.AddSync(os.Stdout)})
+		case SinkStderr:
+			sinks = append(sinks, sink{name: SinkStderr, writer: zapcore.AddSync(os.Stderr)})
+		case SinkFile:
+			if config.FileOptions.Filename == "" {
+				continue
+			}
+			fileSink, err := newFileSink(SinkFile, config.FileOptions, config, false)
+			if err != nil {
+				closeSinks(sinks)
+				return nil, err
+			}
+			sinks = append(sinks, fileSink)
+		default:
+			writer, closeWriter, err := zap.Open(path)
+			if err != nil {
+				closeSinks(sinks)
+				return nil, fmt.Errorf("logger: open output %q: %w", path, err)
+			}
+			writer = newFallbackWriter(path, writer, config)
+			sinks = append(sinks, sink{name: path, writer: writer, close: func() error {
+				closeWriter()
+				return nil
+			}})
+		}
+		opened[name] = true
+	}
+
+	if config.FileOptions.Filename != "" && !opened[SinkFile] {
+		fileSink, err := newFileSink(SinkFile, config.FileOptions, config, false)
+		if err != nil {
+			closeSinks(sinks)
+			return nil, err
+		}
+		sinks = append(sinks, fileSink)
+	}
+	return sinks, nil
+}
+
+// SinkFactory creates the sink for an output URL whose scheme it was
+// registered for
+type SinkFactory func(u *url.URL) (zap.Sink, error)
+
+// RegisterSink registers a factory for OutputPaths entries with the given
+// URL scheme, e.g. "syslog" for "syslog://localhost:514". Schemes are global
+// and can be registered only once.
+func RegisterSink(scheme string, factory SinkFactory) error {
+	if factory == nil {
+		return fmt.Errorf("logger: nil sink factory for scheme %q", scheme)
+	}
+	if err := zap.RegisterSink(scheme, factory); err != nil {
+		return fmt.Errorf("logger: register sink: %w", err)
+	}
+	registeredSchemes.Store(strings.ToLower(scheme), true)
+	return nil
+}
+
+// registeredSchemes are the URL schemes registered through RegisterSink,
+// checked by ValidateStrict
+var registeredSchemes sync.Map
+
+// knownOutputPath reports whether zap.Open can open an OutputPaths entry
+// other than the standard streams and the rotating file: a file path or a
+// URL whose scheme is "file" or registered. Bare words such as "stdot" are
+// taken for misspelled sink names rather than relative file names.
+func knownOutputPath(path string) bool {
+	if path == "" {
+		return false
+	}
+	if u, err := url.Parse(path); err == nil && len(u.Scheme) > 1 {
+		_, ok := registeredSchemes.Load(strings.ToLower(u.Scheme))
+		return ok || u.Scheme == "file"
+	}
+	return strings.ContainsAny(path, `/\.`)
+}
+
+// newFileSink opens a log file with its rotation, disk full, fallback and
+// buffering options. A durable sink, for the audit log, returns write errors
+// instead of applying the disk full policy and the fallback output, and
+// syncs the file to disk on Sync.
+func newFileSink(name string, options FileOptions, config Config, durable bool) (sink, error) {
+	options.Filename = expandFilename(options.Filename)
+	fileWriter, err := newFileWriter(options)
+	if err != nil {
+		return sink{}, err
+	}
+	if trw, ok := fileWriter.(*TimeRotatingWriter); ok {
+		trw.mu.Lock()
+		trw.report = config.internalErrorHandler()
+		trw.mu.Unlock()
+	}
+	writer := zapcore.AddSync(fileWriter)
+	var onRotate []rotateFunc
+	if recorder, ok := config.Metrics.(RotationRecorder); ok {
+		onRotate = append(onRotate, func(_ string, t time.Time) { recorder.RecordRotation(name, t) })
+	}
+	if retention := newBackupRetention(options, fileWriter); retention != nil {
+		retention.enforce()
+		onRotate = append(onRotate, func(string, time.Time) { retention.enforce() })
+	}
+	hook, err := newRotateHook(options, config, activeFilename(fileWriter, options))
+	if err != nil {
+		return sink{}, err
+	}
+	if hook != nil {
+		onRotate = append(onRotate, hook)
+	}
+	var rotate func() error
+	if rotator, ok := fileWriter.(interface{ Rotate() error }); ok {
+		rotate = rotator.Rotate
+	}
+	if len(onRotate) > 0 {
+		watcher := newRotationWatcher(writer, fileWriter, options, onRotate...)
+		if rotate != nil {
+			rotate = watcher.rotateWith(rotate)
+		}
+		writer = watcher
+	}
+	var lock io.Closer
+	if options.Lock {
+		locked, err := newLockedWriter(writer, fileWriter, options)
+		if err != nil {
+			return sink{}, err
+		}
+		writer, lock = locked, locked
+	}
+	if durable {
+		writer = durableWriter{WriteSyncer: writer, active: activeFilename(fileWriter, options)}
+	} else {
+		writer = newDiskFullWriter(name, writer, fileWriter, options, config)
+		writer = newFallbackWriter(name, writer, config)
+	}
+	fileSink := newBufferedSink(name, writer, options)
+	fileSink.level = options.Level
+	if rotate != nil {
+		fileSink.rotate = func() error {
+			// Flush buffered entries into the file being rotated
+			if err := fileSink.writer.Sync(); err != nil {
+				return err
+			}
+			return rotate()
+		}
+	}
+	if closer, ok := fileWriter.(io.Closer); ok {
+		stop := fileSink.close
+		fileSink.close = func() error {
+			var err error
+			if stop != nil {
+				err = stop()
+			}
+			return errors.Join(err, closer.Close())
+		}
+	}
+	if options.ReopenInterval > 0 {
+		if stopWatch := watchExternalRotation(fileWriter, options); stopWatch != nil {
+			stop := fileSink.close
+			fileSink.close = func() error {
+				stopWatch()
+				if stop != nil {
+					return stop()
+				}
+				return nil
+			}
+		}
+	}
+	if lock != nil {
+		stop := fileSink.close
+		fileSink.close = func() error {
+			return errors.Join(stop(), lock.Close())
+		}
+	}
+	return fileSink, nil
+}
+
+// durableWriter syncs the active file to disk on Sync
+type durableWriter struct {
+	zapcore.WriteSyncer
+	active func() string
+}
+
+func (w durableWriter) Sync() error {
+	if err := w.WriteSyncer.Sync(); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(w.active(), os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	return errors.Join(f.Sync(), f.Close())
+}
+
+// newFileWriter creates the rotating writer for a log file. Rotated files
+// are compressed by the rotation hook rather than by lumberjack.
+func newFileWriter(options FileOptions) (io.Writer, error) {
+	options.Compress = false
+
+	// Create directory if needed
+	if options.CreateDir {
+		dir := filepath.Dir(options.Filename)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	// Choose writer based on rotation mode
+	switch options.RotationMode {
+	case RotationModeNone:
+		return openReopenableFile(startFilename(options), options.FileMode)
+	case RotationModeTime, RotationModeBoth:
+		if !validRotationIntervals[options.TimeRotationInterval] {
+			return nil, fmt.Errorf("logger: invalid time rotation interval %q", options.TimeRotationInterval)
+		}
+		if _, err := parseRotationTime(options.RotationTime); err != nil {
+			return nil, err
+		}
+		if _, err := parseWeekday(options.WeekStartDay); err != nil {
+			return nil, err
+		}
+		// Use time-based rotating writer
+		return NewTimeRotatingWriter(options), nil
+	default:
+		// Use size-based rotating writer (lumberjack)
+		return &lumberjack.Logger{
+			Filename:   startFilename(options),
+			MaxSize:    options.MaxSize,
+			MaxAge:     options.MaxAge,
+			MaxBackups: options.MaxBackups,
+			LocalTime:  options.LocalTime,
+			Compress:   options.Compress,
+		}, nil
+	}
+}
+
+// newBufferedSink creates a sink, buffering its writes if the file options
+// ask for it
+func newBufferedSink(name string, writer zapcore.WriteSyncer, options FileOptions) sink {
+	if options.BufferSize <= 0 && options.FlushInterval <= 0 {
+		return sink{name: name, writer: writer}
+	}
+	buffered := &zapcore.BufferedWriteSyncer{
+		WS:            writer,
+		Size:          options.BufferSize,
+		FlushInterval: options.FlushInterval,
+	}
+	return sink{name: name, writer: buffered, close: buffered.Stop}
+}
+
+// newSinkCore creates the core writing to one sink with its own options
+func newSinkCore(encoder zapcore.Encoder, s sink, options SinkOptions) (zapcore.Core, error) {
+	name := s.level
+	if options.Level != "" {
+		name = options.Level
+	}
+	level := zapcore.DebugLevel
+	if name != "" {
+		l, err := ParseLevel(name)
+		if err != nil {
+			return nil, fmt.Errorf("logger: invalid level %q of output %q", name, s.name)
+		}
+		level = severityOf(l)
+	}
+	core := zapcore.NewCore(encoder, s.writer, level)
+	if len(options.KeepFields) > 0 || len(options.DropFields) > 0 {
+		core = newFieldFilterCore(core, options)
+	}
+	return core, nil
+}
+
+// newFieldFilterCore drops fields per the sink's keep and drop lists
+func newFieldFilterCore(core zapcore.Core, options SinkOptions) zapcore.Core {
+	keep := stringSet(options.KeepFields)
+	drop := stringSet(options.DropFields)
+	filter := func(fields []zapcore.Field) []zapcore.Field {
+		out := make([]zapcore.Field, 0, len(fields))
+		for _, f := range fields {
+			key := fieldKey(f)
+			if _, dropped := drop[key]; dropped {
+				continue
+			}
+			if _, kept := keep[key]; len(keep) > 0 && !kept {
+				continue
+			}
+			out = append(out, f)
+		}
+		return out
+	}
+	return newTransformCore(core, &entryTransform{
+		with: filter,
+		write: func(_ *zapcore.Entry, fields []zapcore.Field) ([]zapcore.Field, bool) {
+			return filter(fields), true
+		},
+	})
+}
+
+func stringSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
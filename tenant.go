@@ -0,0 +1,306 @@
+package logger
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// defaultTenantMaxOpen is the number of tenant files kept open by default
+const defaultTenantMaxOpen = 100
+
+// tenantRouter writes entries to one rotating file per tenant. Files are
+// opened on first use and the least recently used ones are closed beyond
+// TenantOptions.MaxOpen. mu guards the set of open files; writes to a file
+// are serialized by the lock of that file only.
+type tenantRouter struct {
+	config  Config
+	options TenantOptions
+	encoder zapcore.Encoder
+	level   zapcore.Level
+	maxOpen int
+
+	mu    sync.Mutex
+	files map[string]*list.Element
+	lru   *list.List // of *tenantFile, most recently used first
+}
+
+// tenantFile is the open file of a tenant
+type tenantFile struct {
+	tenant string
+	sink   sink
+	core   zapcore.Core
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func newTenantRouter(config Config, encoders *encoderSet) (*tenantRouter, error) {
+	options := config.Tenants
+	if !strings.Contains(options.File.Filename, tenantPlaceholder) {
+		return nil, fmt.Errorf("logger: tenant file name %q must contain %s", options.File.Filename, tenantPlaceholder)
+	}
+	encoder, err := encoders.get(options.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	level := zapcore.DebugLevel
+	if options.File.Level != "" {
+		l, err := ParseLevel(options.File.Level)
+		if err != nil {
+			return nil, fmt.Errorf("logger: invalid level %q of tenant files", options.File.Level)
+		}
+		level = severityOf(l)
+	}
+	maxOpen := options.MaxOpen
+	if maxOpen <= 0 {
+		maxOpen = defaultTenantMaxOpen
+	}
+	return &tenantRouter{
+		config:  config,
+		options: options,
+		encoder: encoder,
+		level:   level,
+		maxOpen: maxOpen,
+		files:   make(map[string]*list.Element),
+		lru:     list.New(),
+	}, nil
+}
+
+// enabled reports whether entries at level are written to tenant files
+func (r *tenantRouter) enabled(l zapcore.Level) bool {
+	return severityOf(l) >= r.level
+}
+
+// write writes an entry to the file of tenant
+func (r *tenantRouter) write(tenant string, ent zapcore.Entry, fields []zapcore.Field) error {
+	for {
+		f, err := r.open(tenant)
+		if err != nil {
+			return err
+		}
+		f.mu.Lock()
+		if f.closed {
+			// Closed as least recently used between open and now
+			f.mu.Unlock()
+			continue
+		}
+		err = f.core.Write(ent, fields)
+		f.mu.Unlock()
+		return err
+	}
+}
+
+// open returns the file of tenant, opening it and closing the least
+// recently used files if needed
+func (r *tenantRouter) open(tenant string) (*tenantFile, error) {
+	r.mu.Lock()
+	if elem, ok := r.files[tenant]; ok {
+		r.lru.MoveToFront(elem)
+		r.mu.Unlock()
+		return elem.Value.(*tenantFile), nil
+	}
+
+	options := r.options.File
+	options.Filename = strings.ReplaceAll(options.Filename, tenantPlaceholder, tenantFilename(tenant))
+	// All tenant files share one sink name, so that metrics labels stay
+	// bounded whatever the number of tenants
+	s, err := newFileSink("tenant", options, r.config, false)
+	if err != nil {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("logger: open file of tenant %q: %w", tenant, err)
+	}
+	f := &tenantFile{tenant: tenant, sink: s, core: zapcore.NewCore(r.encoder, s.writer, zapcore.DebugLevel)}
+	r.files[tenant] = r.lru.PushFront(f)
+
+	var evicted []*tenantFile
+	for r.lru.Len() > r.maxOpen {
+		oldest := r.lru.Remove(r.lru.Back()).(*tenantFile)
+		delete(r.files, oldest.tenant)
+		evicted = append(evicted, oldest)
+	}
+	r.mu.Unlock()
+
+	// Close outside of mu so that a slow write to an evicted file does not
+	// block the other tenants
+	for _, oldest := range evicted {
+		if err := closeTenantFile(oldest); err != nil {
+			r.config.internalErrorHandler()(err)
+		}
+	}
+	return f, nil
+}
+
+// sync flushes the open tenant files
+func (r *tenantRouter) sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []error
+	for elem := r.lru.Front(); elem != nil; elem = elem.Next() {
+		f := elem.Value.(*tenantFile)
+		f.mu.Lock()
+		errs = append(errs, f.sink.writer.Sync())
+		f.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// rotate forces the rotation of the open tenant files
+func (r *tenantRouter) rotate() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []error
+	for elem := r.lru.Front(); elem != nil; elem = elem.Next() {
+		f := elem.Value.(*tenantFile)
+		if f.sink.rotate != nil {
+			f.mu.Lock()
+			errs = append(errs, f.sink.rotate())
+			f.mu.Unlock()
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// close closes the open tenant files
+func (r *tenantRouter) close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []error
+	for elem := r.lru.Front(); elem != nil; elem = elem.Next() {
+		errs = append(errs, closeTenantFile(elem.Value.(*tenantFile)))
+	}
+	r.files = make(map[string]*list.Element)
+	r.lru.Init()
+	return errors.Join(errs...)
+}
+
+// closeTenantFile flushes and closes the file of a tenant once its pending
+// write is done
+func closeTenantFile(f *tenantFile) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	err := f.sink.writer.Sync()
+	if f.sink.close != nil {
+		err = errors.Join(err, f.sink.close())
+	}
+	return err
+}
+
+// tenantFilename makes a tenant safe for use in a filename, so that a
+// tenant cannot name a file outside the tenant directory. Lowercase
+// letters, digits, '-' and '.' are kept, except for a leading '.'; every
+// other byte is escaped as '_' and two hex digits. The escaping is
+// injective, also on case-insensitive file systems: "acme/x", "acme_x"
+// and "Acme" get distinct files. The empty tenant is written to "_".
+func tenantFilename(tenant string) string {
+	if tenant == "" {
+		return "_"
+	}
+	var b strings.Builder
+	for i := 0; i < len(tenant); i++ {
+		c := tenant[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '.' && i > 0:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "_%02x", c)
+		}
+	}
+	return b.String()
+}
+
+// tenantCore routes entries carrying the tenant field to the file of their
+// tenant, in addition to or instead of the wrapped outputs
+type tenantCore struct {
+	zapcore.Core
+	router *tenantRouter
+
+	// fields are the fields added through With, written to tenant files
+	// along with the entry fields
+	fields []zapcore.Field
+
+	// tenant is the value of the tenant field added through With, if any
+	tenant    string
+	hasTenant bool
+}
+
+func newTenantCore(core zapcore.Core, router *tenantRouter) zapcore.Core {
+	return &tenantCore{Core: core, router: router}
+}
+
+func (c *tenantCore) Enabled(l zapcore.Level) bool {
+	return c.Core.Enabled(l) || c.router.enabled(l)
+}
+
+func (c *tenantCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	clone.fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	if tenant, ok := c.tenantField(fields); ok {
+		clone.tenant, clone.hasTenant = tenant, true
+	}
+	return &clone
+}
+
+func (c *tenantCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	inner := c.Core.Check(ent, nil)
+	if inner == nil && !c.router.enabled(ent.Level) {
+		return ce
+	}
+	return ce.AddCore(ent, &tenantWriter{core: c, checked: inner})
+}
+
+func (c *tenantCore) Sync() error {
+	return errors.Join(c.Core.Sync(), c.router.sync())
+}
+
+// tenantField returns the value of the tenant field among fields
+func (c *tenantCore) tenantField(fields []zapcore.Field) (string, bool) {
+	return fieldValue(fields, c.router.options.Field)
+}
+
+// tenantWriter is a single-use core that writes an entry to the file of its
+// tenant and through the CheckedEntry of the wrapped outputs
+type tenantWriter struct {
+	core    *tenantCore
+	checked *zapcore.CheckedEntry
+}
+
+func (w *tenantWriter) Enabled(zapcore.Level) bool { return true }
+
+func (w *tenantWriter) With([]zapcore.Field) zapcore.Core { return w }
+
+func (w *tenantWriter) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, w)
+}
+
+func (w *tenantWriter) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c := w.core
+	tenant, ok := c.tenantField(fields)
+	if !ok {
+		tenant, ok = c.tenant, c.hasTenant
+	}
+	routed := ok && c.router.enabled(ent.Level)
+	var err error
+	if routed {
+		all := append(c.fields[:len(c.fields):len(c.fields)], fields...)
+		err = c.router.write(tenant, ent, all)
+	}
+	if w.checked != nil && (!routed || !c.router.options.Exclusive) {
+		err = errors.Join(err, writeChecked(w.checked, ent, fields))
+	}
+	return err
+}
+
+func (w *tenantWriter) Sync() error { return nil }
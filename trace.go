@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// traceFields returns the trace_id, span_id and trace_flags fields of the
+// OpenTelemetry span active in ctx, if any
+func traceFields(ctx context.Context) []zap.Field {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return []zap.Field{
+		zap.String("trace_id", sc.TraceID().String()),
+		zap.String("span_id", sc.SpanID().String()),
+		zap.String("trace_flags", sc.TraceFlags().String()),
+	}
+}
+
+// TraceparentHeader is the W3C Trace Context header carrying the trace
+const TraceparentHeader = "traceparent"
+
+// ContextWithTraceparent returns ctx carrying the trace and parent span IDs
+// of a W3C traceparent header value, such as
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", so that the
+// context-aware log methods add them to entries without the OpenTelemetry
+// SDK. The IDs are stored as a remote OpenTelemetry span context, which an
+// SDK tracer also continues from.
+func ContextWithTraceparent(ctx context.Context, traceparent string) (context.Context, error) {
+	sc, err := parseTraceparent(traceparent)
+	if err != nil {
+		return ctx, err
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc), nil
+}
+
+// parseTraceparent parses a traceparent header value
+func parseTraceparent(value string) (trace.SpanContext, error) {
+	invalid := fmt.Errorf("logger: invalid traceparent %q", value)
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return trace.SpanContext{}, invalid
+	}
+	// Version 00 has exactly four parts; later versions may append more
+	if parts[0] == "00" && len(parts) != 4 {
+		return trace.SpanContext{}, invalid
+	}
+	if _, err := hex.DecodeString(parts[0]); err != nil || !isLowerHex(parts[1]) || !isLowerHex(parts[2]) {
+		return trace.SpanContext{}, invalid
+	}
+	traceID, err := trace.TraceIDFromHex(parts[1])
+	if err != nil {
+		return trace.SpanContext{}, invalid
+	}
+	spanID, err := trace.SpanIDFromHex(parts[2])
+	if err != nil {
+		return trace.SpanContext{}, invalid
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil || len(flags) != 1 {
+		return trace.SpanContext{}, invalid
+	}
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.TraceFlags(flags[0]),
+		Remote:     true,
+	}), nil
+}
+
+func isLowerHex(s string) bool {
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"errors"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// entryTransform rewrites entries and fields on their way to a core
+type entryTransform struct {
+	// with rewrites fields added through With; nil leaves them unchanged
+	with func(fields []zapcore.Field) []zapcore.Field
+
+	// write rewrites an entry and its per-call fields. Returning false drops
+	// the entry. nil leaves entries unchanged.
+	write func(ent *zapcore.Entry, fields []zapcore.Field) ([]zapcore.Field, bool)
+}
+
+// transformCore applies an entryTransform in front of a core. Unlike a plain
+// wrapper it keeps the decisions the wrapped core makes in Check (levels,
+// sampling, tees of sinks with different levels) by writing through the
+// CheckedEntry the wrapped core produced.
+type transformCore struct {
+	zapcore.Core
+	transform *entryTransform
+}
+
+func newTransformCore(core zapcore.Core, transform *entryTransform) zapcore.Core {
+	return &transformCore{Core: core, transform: transform}
+}
+
+func (c *transformCore) With(fields []zapcore.Field) zapcore.Core {
+	if c.transform.with != nil {
+		fields = c.transform.with(fields)
+	}
+	return &transformCore{Core: c.Core.With(fields), transform: c.transform}
+}
+
+func (c *transformCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	inner := c.Core.Check(ent, nil)
+	if inner == nil {
+		return ce
+	}
+	return ce.AddCore(inner.Entry, &transformWriter{checked: inner, transform: c.transform})
+}
+
+// transformWriter is a single-use core that writes one transformed entry
+// through the CheckedEntry of the wrapped core
+type transformWriter struct {
+	checked   *zapcore.CheckedEntry
+	transform *entryTransform
+}
+
+func (w *transformWriter) Enabled(zapcore.Level) bool { return true }
+
+func (w *transformWriter) With([]zapcore.Field) zapcore.Core { return w }
+
+func (w *transformWriter) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, w)
+}
+
+func (w *transformWriter) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if w.transform.write != nil {
+		var keep bool
+		if fields, keep = w.transform.write(&ent, fields); !keep {
+			return nil
+		}
+	}
+	return writeChecked(w.checked, ent, fields)
+}
+
+func (w *transformWriter) Sync() error { return nil }
+
+// writeChecked writes an entry through the CheckedEntry of a wrapped core
+// and returns the errors of its cores. CheckedEntry.Write only prints these
+// to its ErrorOutput, which the CheckedEntry of a wrapped core lacks, so
+// they are captured and returned to the outer CheckedEntry instead.
+func writeChecked(checked *zapcore.CheckedEntry, ent zapcore.Entry, fields []zapcore.Field) error {
+	var out checkedErrorOutput
+	checked.Entry = ent
+	checked.ErrorOutput = &out
+	checked.Write(fields...)
+	return out.err
+}
+
+// checkedErrorOutput turns the messages CheckedEntry.Write prints for failed
+// writes back into an error
+type checkedErrorOutput struct {
+	err error
+}
+
+func (o *checkedErrorOutput) Write(p []byte) (int, error) {
+	msg := strings.TrimSpace(string(p))
+	// Strip the "<time> write error: " prefix
+	if _, cause, ok := strings.Cut(msg, " write error: "); ok {
+		msg = cause
+	}
+	o.err = errors.Join(o.err, errors.New(msg))
+	return len(p), nil
+}
+
+func (o *checkedErrorOutput) Sync() error { return nil }
@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+)
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk on fire") }
+
+func TestTransformCoreReportsWriteErrors(t *testing.T) {
+	configs := map[string]Config{
+		"plain":     TestConfig(),
+		"redaction": TestConfig().WithRedaction(),
+		"scrubbing": TestConfig().WithScrubbingPresets("email"),
+	}
+	for name, config := range configs {
+		t.Run(name, func(t *testing.T) {
+			var reported []error
+			config.Level = LevelInfo
+			config.OnInternalError = func(err error) { reported = append(reported, err) }
+			log, err := NewLoggerWithWriter(config, failingWriter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			log.Info("hello")
+			if len(reported) != 1 {
+				t.Fatalf("got %d reported errors, want 1: %v", len(reported), reported)
+			}
+		})
+	}
+}
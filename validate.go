@@ -0,0 +1,306 @@
+package logger
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Accepted values of the enumerated options. Empty selects the default
+// where it is accepted.
+var (
+	validEnvironments = map[string]bool{
+		EnvDevelopment: true,
+		EnvStaging:     true,
+		EnvProduction:  true,
+		EnvTest:        true,
+	}
+	validEncodings = map[string]bool{
+		EncodingJSON:       true,
+		EncodingJSONPretty: true,
+		EncodingConsole:    true,
+	}
+	validColorModes = map[string]bool{
+		"":              true,
+		ColorModeAuto:   true,
+		ColorModeAlways: true,
+		ColorModeNever:  true,
+	}
+	validTimeEncodings = map[string]bool{
+		"":                      true,
+		TimeEncodingISO8601:     true,
+		TimeEncodingRFC3339:     true,
+		TimeEncodingRFC3339Nano: true,
+		TimeEncodingEpoch:       true,
+		TimeEncodingEpochMillis: true,
+		TimeEncodingEpochNanos:  true,
+	}
+	validCallerFormats = map[string]bool{
+		"":                true,
+		CallerFormatShort: true,
+		CallerFormatFull:  true,
+		CallerFormatNone:  true,
+	}
+	validFallbackOutputs = map[string]bool{
+		"":             true,
+		FallbackStderr: true,
+		FallbackStdout: true,
+		FallbackNone:   true,
+	}
+	validDiskFullPolicies = map[string]bool{
+		"":             true,
+		DiskFullDrop:   true,
+		DiskFullStdout: true,
+		DiskFullPurge:  true,
+	}
+	validCompressions = map[string]bool{
+		"":              true,
+		CompressionGzip: true,
+		CompressionZstd: true,
+		CompressionNone: true,
+	}
+	validRotationModes = map[RotationMode]bool{
+		"":               true,
+		RotationModeSize: true,
+		RotationModeTime: true,
+		RotationModeBoth: true,
+		RotationModeNone: true,
+	}
+	validRotationIntervals = map[TimeRotationInterval]bool{
+		"":              true,
+		RotationHourly:  true,
+		RotationDaily:   true,
+		RotationWeekly:  true,
+		RotationMonthly: true,
+	}
+)
+
+// ValidateStrict reports every invalid setting of the configuration, such
+// as unknown levels, encodings or rotation options and settings that have
+// no effect in their combination, e.g. to reject typos in deployment
+// manifests, and the environment variables ConfigFromEnv could not parse.
+// Unlike the constructors, which fall back to defaults, it changes nothing.
+// It returns nil if the configuration is valid.
+func (c Config) ValidateStrict() []error {
+	v := &validator{}
+	v.errs = append(v.errs, c.envErrors...)
+
+	v.level("level", c.Level)
+	if c.Environment != "" {
+		v.oneOf("environment", c.Environment, validEnvironments)
+	}
+	if c.Encoding != "" {
+		v.oneOf("encoding", c.Encoding, validEncodings)
+	}
+	v.oneOf("colors.mode", c.Colors.Mode, validColorModes)
+	v.oneOf("time_encoding", c.TimeEncoding, validTimeEncodings)
+	v.oneOf("caller_format", c.CallerFormat, validCallerFormats)
+	v.oneOf("fallback_output", c.FallbackOutput, validFallbackOutputs)
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			v.add("logger: invalid timezone %q: %w", c.Timezone, err)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.Levels)) {
+		v.level(fmt.Sprintf("levels[%s]", name), c.Levels[name])
+	}
+
+	// Outputs
+	sinkNames := map[string]bool{SinkStdout: true, SinkStderr: true, SinkFile: true, SinkWriter: true, SinkAudit: true}
+	for _, path := range c.OutputPaths {
+		switch {
+		case path == SinkFile && c.FileOptions.Filename == "":
+			v.add("logger: output %q needs file_options.filename", SinkFile)
+		case sinkNames[path] || path == c.FileOptions.Filename:
+		case !knownOutputPath(path):
+			v.add("logger: unknown output %q", path)
+		}
+		sinkNames[path] = true
+	}
+	for _, file := range c.Files {
+		if file.Name != "" {
+			sinkNames[file.Name] = true
+		} else {
+			sinkNames[file.Filename] = true
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Sinks)) {
+		if !sinkNames[name] {
+			v.add("logger: sinks[%s] names no output", name)
+		}
+		sink := c.Sinks[name]
+		if sink.Encoding != "" {
+			v.oneOf(fmt.Sprintf("sinks[%s].encoding", name), sink.Encoding, validEncodings)
+		}
+		if sink.Level != "" {
+			v.level(fmt.Sprintf("sinks[%s].level", name), sink.Level)
+		}
+	}
+
+	// Files
+	v.file("file_options", c.FileOptions)
+	for i, file := range c.Files {
+		v.file(fmt.Sprintf("files[%d]", i), file)
+	}
+	if c.Audit.File.Filename != "" {
+		v.file("audit.file", c.Audit.File)
+	}
+	if c.Tenants.Field != "" {
+		v.file("tenants.file", c.Tenants.File)
+		if !strings.Contains(c.Tenants.File.Filename, tenantPlaceholder) {
+			v.add("logger: tenants.file.filename %q must contain %s", c.Tenants.File.Filename, tenantPlaceholder)
+		}
+		if c.Tenants.Encoding != "" {
+			v.oneOf("tenants.encoding", c.Tenants.Encoding, validEncodings)
+		}
+	}
+
+	// Sampling
+	if c.Sampling.Enabled {
+		v.nonNegative("sampling.initial", c.Sampling.Initial)
+		v.nonNegative("sampling.thereafter", c.Sampling.Thereafter)
+		v.nonNegative("sampling.tick", int(c.Sampling.Tick))
+		for _, level := range slices.Sorted(maps.Keys(c.Sampling.Levels)) {
+			rate := c.Sampling.Levels[level]
+			v.level("sampling.levels key", level)
+			v.nonNegative(fmt.Sprintf("sampling.levels[%s].initial", level), rate.Initial)
+			v.nonNegative(fmt.Sprintf("sampling.levels[%s].thereafter", level), rate.Thereafter)
+		}
+		if c.Sampling.UnsampledLevel != "" {
+			v.level("sampling.unsampled_level", c.Sampling.UnsampledLevel)
+		}
+	}
+
+	if c.RateLimit.UnlimitedLevel != "" {
+		v.level("rate_limit.unlimited_level", c.RateLimit.UnlimitedLevel)
+	}
+
+	// Scrubbing and rules
+	if _, err := newScrubber(c.Scrubbing); err != nil {
+		v.errs = append(v.errs, err)
+	}
+	for _, f := range c.Drop {
+		if _, err := compileDropFilter(f); err != nil {
+			v.errs = append(v.errs, err)
+		}
+	}
+	for _, o := range c.LevelOverrides {
+		if _, err := compileLevelOverride(o); err != nil {
+			v.errs = append(v.errs, err)
+		}
+	}
+	for _, r := range c.Routes {
+		if _, err := compileRoute(r); err != nil {
+			v.errs = append(v.errs, err)
+		}
+	}
+
+	return v.errs
+}
+
+// validator collects the errors of ValidateStrict
+type validator struct {
+	errs []error
+}
+
+func (v *validator) add(format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf(format, args...))
+}
+
+func (v *validator) level(option, level string) {
+	if _, err := ParseLevel(level); err != nil {
+		v.add("logger: invalid %s %q", option, level)
+	}
+}
+
+func (v *validator) oneOf(option, value string, valid map[string]bool) {
+	if !valid[value] {
+		v.add("logger: invalid %s %q", option, value)
+	}
+}
+
+func (v *validator) nonNegative(option string, value int) {
+	if value < 0 {
+		v.add("logger: %s must not be negative, got %d", option, value)
+	}
+}
+
+// file validates the options of a log file and their combination
+func (v *validator) file(option string, o FileOptions) {
+	if o.Level != "" {
+		v.level(option+".level", o.Level)
+	}
+	v.nonNegative(option+".max_size", o.MaxSize)
+	v.nonNegative(option+".max_age", o.MaxAge)
+	v.nonNegative(option+".max_backups", o.MaxBackups)
+	v.nonNegative(option+".max_total_size", o.MaxTotalSize)
+	v.nonNegative(option+".compress_delay", o.CompressDelay)
+	v.nonNegative(option+".buffer_size", o.BufferSize)
+	v.nonNegative(option+".flush_interval", int(o.FlushInterval))
+	v.nonNegative(option+".reopen_interval", int(o.ReopenInterval))
+	v.oneOf(option+".disk_full_policy", o.DiskFullPolicy, validDiskFullPolicies)
+	if _, err := parseRecipients(o.EncryptRecipients); err != nil {
+		v.errs = append(v.errs, err)
+	}
+
+	// Compression
+	v.oneOf(option+".compression", strings.ToLower(o.Compression), validCompressions)
+	if o.CompressionLevel != 0 {
+		switch o.compression() {
+		case CompressionGzip:
+			if o.CompressionLevel < 1 || o.CompressionLevel > 9 {
+				v.add("logger: %s.compression_level must be 1 to 9 for gzip, got %d", option, o.CompressionLevel)
+			}
+		case CompressionZstd:
+			if o.CompressionLevel < 1 || o.CompressionLevel > 22 {
+				v.add("logger: %s.compression_level must be 1 to 22 for zstd, got %d", option, o.CompressionLevel)
+			}
+		default:
+			v.add("logger: %s.compression_level is set without compression", option)
+		}
+	}
+
+	// Rotation
+	if !validRotationModes[o.RotationMode] {
+		v.add("logger: invalid %s.rotation_mode %q", option, o.RotationMode)
+		return
+	}
+	if !validRotationIntervals[o.TimeRotationInterval] {
+		v.add("logger: invalid %s.time_rotation_interval %q", option, o.TimeRotationInterval)
+	}
+	if _, err := parseRotationTime(o.RotationTime); err != nil {
+		v.add("logger: invalid %s.rotation_time %q, want HH:MM", option, o.RotationTime)
+	}
+	if _, err := parseWeekday(o.WeekStartDay); err != nil {
+		v.add("logger: invalid %s.week_start_day %q", option, o.WeekStartDay)
+	}
+	if o.RotationMode != RotationModeTime && o.RotationMode != RotationModeBoth {
+		// Settings of time rotation
+		for _, setting := range []struct {
+			name string
+			set  bool
+		}{
+			{"time_rotation_format", o.TimeRotationFormat != ""},
+			{"rotation_time", o.RotationTime != ""},
+			{"week_start_day", o.WeekStartDay != ""},
+			{"symlink", o.Symlink},
+		} {
+			if setting.set {
+				v.add("logger: %s.%s needs rotation_mode time or both", option, setting.name)
+			}
+		}
+		return
+	}
+	if o.TimeRotationInterval == "" {
+		v.add("logger: %s.time_rotation_interval is needed for rotation_mode %s", option, o.RotationMode)
+	}
+	if o.WeekStartDay != "" && o.TimeRotationInterval != RotationWeekly {
+		v.add("logger: %s.week_start_day needs time_rotation_interval weekly", option)
+	}
+	if o.RotationTime != "" && o.TimeRotationInterval == RotationHourly {
+		v.add("logger: %s.rotation_time does not apply to hourly rotation", option)
+	}
+}